@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"unicode"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Accent is a mark drawn over, or under, a letter to make an accented
+// letter a font image lacks. Each row is a line of pixels, # for a pixel,
+// centered on the letter.
+type Accent struct {
+	Rows  []string
+	Below bool // under the letter, like a cedilla
+}
+
+var (
+	accentAcute      = &Accent{Rows: []string{"..#", ".#."}}
+	accentGrave      = &Accent{Rows: []string{"#..", ".#."}}
+	accentCircumflex = &Accent{Rows: []string{".#.", "#.#"}}
+	accentTilde      = &Accent{Rows: []string{".#.#", "#.#."}}
+	accentDiaeresis  = &Accent{Rows: []string{"#.#"}}
+	accentRing       = &Accent{Rows: []string{".#.", "#.#", ".#."}}
+	accentCedilla    = &Accent{Rows: []string{".#.", "##."}, Below: true}
+)
+
+type accented struct {
+	base   rune
+	accent *Accent
+}
+
+// accentedLetters are the Latin-1 letters and Ÿ as a letter and an accent,
+// in both cases.
+var accentedLetters = func() map[rune]accented {
+	m := map[rune]accented{}
+	add := func(letters, bases string, accent *Accent) {
+		b := []rune(bases)
+		for i, r := range []rune(letters) {
+			m[r] = accented{base: b[i], accent: accent}
+			m[unicode.ToLower(r)] = accented{base: unicode.ToLower(b[i]), accent: accent}
+		}
+	}
+	add("ÁÉÍÓÚÝ", "AEIOUY", accentAcute)
+	add("ÀÈÌÒÙ", "AEIOU", accentGrave)
+	add("ÂÊÎÔÛ", "AEIOU", accentCircumflex)
+	add("ÃÑÕ", "ANO", accentTilde)
+	add("ÄËÏÖÜŸ", "AEIOUY", accentDiaeresis)
+	add("Å", "A", accentRing)
+	add("Ç", "C", accentCedilla)
+	return m
+}()
+
+// draw draws the accent for a letter drawn at pos, scale screen units to an
+// image pixel, touching the top of a cell high letter or under its
+// baseline.
+func (a *Accent) draw(g Glyph, pos rl.Vector2, height, scale float32, color rl.Color) {
+	width := float32(len(a.Rows[0]))
+	x := pos.X + float32(math.Floor(float64(g.Src.Width-1-width)/2+0.5))*scale
+	y := pos.Y - float32(len(a.Rows))*scale
+	if a.Below {
+		y = pos.Y + (height-1)*scale
+	}
+	for row, pixels := range a.Rows {
+		for col, c := range pixels {
+			if c == '#' {
+				rl.DrawRectangleRec(rl.NewRectangle(x+float32(col)*scale, y+float32(row)*scale, scale, scale), color)
+			}
+		}
+	}
+}
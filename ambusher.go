@@ -0,0 +1,70 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Ambusher (Pink)
+//    Chase:
+//       Works with the pack. Instead of a fixed number of tiles ahead like
+//       Pinky, it targets the next intersection Player will reach in her
+//       direction of movement and takes the shortest path there to cut
+//       her off, while the other ghosts come up from behind.
+//    Scatter:
+//       Moves to the top-left corner of the maze.
+
+func (b Ambusher) Id() GhostId {
+	return AmbusherId
+}
+
+func (b Ambusher) Color() rl.Color {
+	return rl.Magenta
+}
+
+func (b Ambusher) StartingTile(game *Game) Vec2i {
+	return Vec2i{X: 14, Y: 11}
+}
+
+func (b Ambusher) StartingDir(game *Game) Direction {
+	return Right
+}
+
+func (b Ambusher) Sprite() Vec2i {
+	return Vec2i{X: 520, Y: 80}
+}
+
+func (b Ambusher) Chase(game *Game, self *Ghost) Vec2i {
+	p := game.player
+	if p.vel.IsZero() {
+		return p.tile
+	}
+
+	tile := game.maze.Wrap(p.tile)
+	for range game.maze.Width() {
+		next := game.maze.Wrap(p.dir.GetNextTile(tile))
+		if !game.maze.IsValidMove(next) {
+			break
+		}
+		tile = next
+		if game.graph.IsIntersection(tile) {
+			break
+		}
+	}
+
+	// already sitting on the ambush point, go straight for her
+	if self.tile == tile {
+		return p.tile
+	}
+
+	return tile
+}
+
+func (b Ambusher) Scatter(game *Game, _ *Ghost) Vec2i {
+	return Vec2i{X: 1, Y: 1} // depends on board
+}
+
+func (b Ambusher) ExitHouse(game *Game, _ *Ghost) bool {
+	return game.levelTime > 1.0
+}
+
+func (b Ambusher) Navigates() bool {
+	return true
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+type SoundId int
+
+const (
+	SoundIntro SoundId = iota
+	SoundMunchA
+	SoundMunchB
+	SoundSiren
+	SoundPowerSiren
+	SoundGhostEaten
+	SoundEyes
+	SoundFruitBounce
+	SoundDeath
+	SoundExtraLife
+
+	NumSounds = int(SoundExtraLife) + 1
+
+	SoundDir        = "sounds"
+	SoundSampleRate = 44100
+	SoundBufferSize = 1024 // samples per audio stream buffer
+)
+
+// oneShots are the effects played once on the effects voice.
+var oneShots = []SoundId{SoundIntro, SoundMunchA, SoundMunchB, SoundGhostEaten, SoundDeath, SoundExtraLife}
+
+func (s SoundId) String() string {
+	switch s {
+	case SoundIntro:
+		return "intro"
+	case SoundMunchA:
+		return "munch_a"
+	case SoundMunchB:
+		return "munch_b"
+	case SoundSiren:
+		return "siren"
+	case SoundPowerSiren:
+		return "power_siren"
+	case SoundGhostEaten:
+		return "ghost_eaten"
+	case SoundEyes:
+		return "eyes"
+	case SoundFruitBounce:
+		return "fruit_bounce"
+	case SoundDeath:
+		return "death"
+	case SoundExtraLife:
+		return "extra_life"
+	default:
+		panic("unhandled default case")
+	}
+}
+
+// AudioBackend plays sounds. RaylibAudio plays them for real, NullAudio
+// just records what would have played for headless runs and tests.
+type AudioBackend interface {
+	// LoadFile replaces the synthesized sound with one from a wav, ogg, mp3
+	// or flac file.
+	LoadFile(id SoundId, file string) error
+	// Update is called once per frame to keep streamed audio fed.
+	Update()
+	Play(id SoundId)
+	Stop(id SoundId)
+	IsPlaying(id SoundId) bool
+	SetVolume(volume float32)
+	Close()
+}
+
+// Audio turns game events into sound effects. It subscribes to the EventBus
+// for one shot effects; the looping sirens and the eyes sound follow the
+// state of the ghosts.
+type Audio struct {
+	backend AudioBackend
+	munch   int
+	loop    SoundId // looping background sound currently playing, or -1
+	volume  float32
+}
+
+// NewAudio loads any sound found in SoundDir under the sound's name (e.g.
+// sounds/munch_a.wav). The rest are synthesized by the WSG sound chip, so
+// the game has sound without any assets.
+func NewAudio(backend AudioBackend) *Audio {
+	for i := range NumSounds {
+		id := SoundId(i)
+		if file := findSoundFile(id); file != "" {
+			if err := backend.LoadFile(id, file); err != nil {
+				fmt.Fprintf(os.Stderr, "sound %s: %v, using synthesized sound\n", file, err)
+			}
+		}
+	}
+
+	return &Audio{backend: backend, loop: -1, volume: 1}
+}
+
+func findSoundFile(id SoundId) string {
+	for _, ext := range []string{".wav", ".ogg", ".mp3", ".flac"} {
+		file := filepath.Join(SoundDir, id.String()+ext)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return ""
+}
+
+// Update keeps the right background loop going and the backend fed. It is
+// called once per frame after the events have been handled.
+func (a *Audio) Update(g *Game) {
+	a.updateLoops(g)
+
+	// the bounce repeats while the fruit is out, but on the effects voice it
+	// would cut off any other effect, so it waits for them to finish
+	if g.fruit != nil && g.phase == PhasePlaying && !a.backend.IsPlaying(SoundFruitBounce) &&
+		!slices.ContainsFunc(oneShots, a.backend.IsPlaying) {
+		a.backend.Play(SoundFruitBounce)
+	}
+
+	a.backend.Update()
+}
+
+// HandleEvent plays the one shot sound for an event.
+func (a *Audio) HandleEvent(_ *Game, _ int, e Event) {
+	switch e.(type) {
+	case GameStarted:
+		a.backend.Play(SoundIntro)
+	case DotEaten, PelletEaten:
+		// the arcade alternates two halves of the munch
+		a.backend.Play(SoundMunchA + SoundId(a.munch))
+		a.munch = 1 - a.munch
+	case GhostEaten, FruitEaten:
+		a.backend.Play(SoundGhostEaten)
+	case PlayerDied:
+		a.stopLoop()
+		a.backend.Play(SoundDeath)
+	case ExtraLife:
+		a.backend.Play(SoundExtraLife)
+	}
+}
+
+func (a *Audio) updateLoops(g *Game) {
+	if g.phase != PhasePlaying {
+		a.stopLoop()
+		return
+	}
+	if a.backend.IsPlaying(SoundIntro) || a.backend.IsPlaying(SoundDeath) {
+		return
+	}
+
+	loop := SoundSiren
+	for _, ghost := range g.ghosts {
+		if ghost.state == Eaten {
+			loop = SoundEyes
+			break
+		}
+		if ghost.state == Frightened {
+			loop = SoundPowerSiren
+		}
+	}
+
+	if loop != a.loop {
+		a.stopLoop()
+		a.loop = loop
+	}
+
+	// raylib sounds don't loop on their own, restart when finished
+	if !a.backend.IsPlaying(loop) {
+		a.backend.Play(loop)
+	}
+}
+
+func (a *Audio) stopLoop() {
+	if a.loop >= 0 {
+		a.backend.Stop(a.loop)
+		a.loop = -1
+	}
+}
+
+func (a *Audio) SetVolume(volume float32) {
+	a.volume = volume
+	a.backend.SetVolume(volume)
+}
+
+func (a *Audio) Volume() float32 {
+	return a.volume
+}
+
+func (a *Audio) Close() {
+	a.backend.Close()
+}
+
+// NullAudio is a silent AudioBackend that remembers what was played.
+type NullAudio struct {
+	Played  []SoundId
+	playing map[SoundId]bool
+}
+
+func NewNullAudio() *NullAudio {
+	return &NullAudio{playing: make(map[SoundId]bool)}
+}
+
+func (n *NullAudio) LoadFile(SoundId, string) error { return nil }
+func (n *NullAudio) Update()                        {}
+func (n *NullAudio) SetVolume(float32)              {}
+func (n *NullAudio) Close()                         {}
+
+func (n *NullAudio) Play(id SoundId) {
+	n.Played = append(n.Played, id)
+	n.playing[id] = true
+}
+
+func (n *NullAudio) Stop(id SoundId) {
+	delete(n.playing, id)
+}
+
+func (n *NullAudio) IsPlaying(id SoundId) bool {
+	return n.playing[id]
+}
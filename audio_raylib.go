@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// RaylibAudio is the AudioBackend that plays through the raylib audio device.
+// Sounds loaded from files play as raylib sounds, everything else is
+// synthesized by a WSG streamed through a raylib audio stream.
+type RaylibAudio struct {
+	sounds [NumSounds]rl.Sound
+	loaded [NumSounds]bool
+	wsg    *WSG
+	stream rl.AudioStream
+	buffer []float32
+}
+
+func NewRaylibAudio() *RaylibAudio {
+	rl.InitAudioDevice()
+	rl.SetAudioStreamBufferSizeDefault(SoundBufferSize)
+
+	r := &RaylibAudio{
+		wsg:    NewWSG(SoundSampleRate),
+		stream: rl.LoadAudioStream(SoundSampleRate, 32, 1),
+		buffer: make([]float32, SoundBufferSize),
+	}
+	rl.PlayAudioStream(r.stream)
+	return r
+}
+
+func (r *RaylibAudio) LoadFile(id SoundId, file string) error {
+	sound := rl.LoadSound(file)
+	if sound.FrameCount == 0 {
+		return fmt.Errorf("unable to load %s", file)
+	}
+	r.sounds[id] = sound
+	r.loaded[id] = true
+	return nil
+}
+
+// Update refills the stream with freshly synthesized samples whenever raylib
+// has used up a buffer.
+func (r *RaylibAudio) Update() {
+	for rl.IsAudioStreamProcessed(r.stream) {
+		r.wsg.Render(r.buffer)
+		rl.UpdateAudioStream(r.stream, r.buffer)
+	}
+}
+
+func (r *RaylibAudio) Play(id SoundId) {
+	if r.loaded[id] {
+		rl.PlaySound(r.sounds[id])
+	} else {
+		r.wsg.Play(id)
+	}
+}
+
+func (r *RaylibAudio) Stop(id SoundId) {
+	if r.loaded[id] {
+		rl.StopSound(r.sounds[id])
+	} else {
+		r.wsg.Stop(id)
+	}
+}
+
+func (r *RaylibAudio) IsPlaying(id SoundId) bool {
+	if r.loaded[id] {
+		return rl.IsSoundPlaying(r.sounds[id])
+	}
+	return r.wsg.IsPlaying(id)
+}
+
+func (r *RaylibAudio) SetVolume(volume float32) {
+	rl.SetMasterVolume(volume)
+}
+
+func (r *RaylibAudio) Close() {
+	for i := range r.sounds {
+		if r.loaded[i] {
+			rl.UnloadSound(r.sounds[i])
+		}
+	}
+	rl.UnloadAudioStream(r.stream)
+	rl.CloseAudioDevice()
+}
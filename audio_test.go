@@ -0,0 +1,60 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAudioEvents(t *testing.T) {
+	null := NewNullAudio()
+	a := NewAudio(null)
+	for _, e := range []Event{
+		GameStarted{},
+		DotEaten{},
+		PelletEaten{},
+		DotEaten{},
+		GhostEaten{},
+		FruitEaten{},
+		ModeChanged{},
+		ExtraLife{},
+	} {
+		a.HandleEvent(nil, 0, e)
+	}
+
+	want := []SoundId{SoundIntro, SoundMunchA, SoundMunchB, SoundMunchA, SoundGhostEaten, SoundGhostEaten, SoundExtraLife}
+	if !slices.Equal(null.Played, want) {
+		t.Errorf("played %v, want %v", null.Played, want)
+	}
+}
+
+// The siren follows the ghosts, and stops for the death.
+func TestAudioLoops(t *testing.T) {
+	null := NewNullAudio()
+	a := NewAudio(null)
+	ghost := &Ghost{state: Chase}
+	g := &Game{phase: PhasePlaying, ghosts: []*Ghost{ghost}}
+
+	for _, tt := range []struct {
+		state GhostState
+		want  SoundId
+	}{
+		{Chase, SoundSiren},
+		{Frightened, SoundPowerSiren},
+		{Eaten, SoundEyes},
+		{Chase, SoundSiren},
+	} {
+		ghost.state = tt.state
+		a.Update(g)
+		for _, loop := range []SoundId{SoundSiren, SoundPowerSiren, SoundEyes} {
+			if playing := null.IsPlaying(loop); playing != (loop == tt.want) {
+				t.Errorf("%s: %s playing %t", tt.state, loop, playing)
+			}
+		}
+	}
+
+	a.HandleEvent(g, 0, PlayerDied{})
+	a.Update(g)
+	if null.IsPlaying(SoundSiren) || !null.IsPlaying(SoundDeath) {
+		t.Error("the siren plays over the death")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultGhosts is the arcade line-up used when no -ghosts flag is given.
+const DefaultGhosts = "blinky,pinky,inky,clyde"
+
+// behaviors maps the names accepted by the -ghosts flag to a constructor for
+// their Behavior. Register new ghost personalities here.
+var behaviors = map[string]func() Behavior{
+	"blinky":   func() Behavior { return Blinky{} },
+	"pinky":    func() Behavior { return Pinky{} },
+	"inky":     func() Behavior { return Inky{} },
+	"clyde":    func() Behavior { return Clyde{} },
+	"sue":      func() Behavior { return Sue{} },
+	"hunter":   func() Behavior { return Hunter{} },
+	"ambusher": func() Behavior { return Ambusher{} },
+	"wanderer": func() Behavior { return &Wanderer{} },
+}
+
+// BehaviorNames returns the registered behavior names in sorted order.
+func BehaviorNames() []string {
+	names := make([]string, 0, len(behaviors))
+	for name := range behaviors {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseBehaviors turns a comma separated list of behavior names, such as
+// "blinky,pinky,hunter", into behaviors, one ghost per name. Names ending in
+// .star are loaded as Starlark ghost scripts.
+func ParseBehaviors(list string) ([]Behavior, error) {
+	var result []Behavior
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if strings.HasSuffix(name, ScriptExt) {
+			b, err := NewScriptBehavior(name)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, b)
+			continue
+		}
+
+		newBehavior, ok := behaviors[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown ghost %q, expected one of %s", name, strings.Join(BehaviorNames(), ", "))
+		}
+		result = append(result, newBehavior())
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no ghosts in %q", list)
+	}
+
+	return result, nil
+}
+
+// findGhost returns the first ghost playing the given role, or nil if there
+// is none. Behaviors use it to find collaborators, e.g. Inky needs Blinky;
+// the ghost a behavior drives is passed to it as self.
+func (g *Game) findGhost(id GhostId) *Ghost {
+	for _, ghost := range g.ghosts {
+		if ghost.id == id {
+			return ghost
+		}
+	}
+	return nil
+}
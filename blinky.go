@@ -29,14 +29,14 @@ func (b Blinky) Sprite() Vec2i {
 	return Vec2i{X: 520, Y: 64}
 }
 
-func (b Blinky) Chase(game *Game) Vec2i {
+func (b Blinky) Chase(game *Game, self *Ghost) Vec2i {
 	return game.player.tile
 }
 
-func (b Blinky) Scatter(game *Game) Vec2i {
+func (b Blinky) Scatter(game *Game, _ *Ghost) Vec2i {
 	return Vec2i{X: 26, Y: 1} // depends on board
 }
 
-func (b Blinky) ExitHouse(_ *Game) bool {
+func (b Blinky) ExitHouse(_ *Game, _ *Ghost) bool {
 	return true
 }
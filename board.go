@@ -1 +1,318 @@
 package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Board is a playable maze and the places on it the rules need to know
+// about. The six built-in boards are read from the spritesheet; custom
+// boards are loaded from maze files or made in the editor.
+type Board struct {
+	Name   string
+	Maze   Maze    // the tiles, with every dot in place
+	Player Vec2i   // where the player starts
+	Ghost  Vec2i   // above the house door: ghosts start, leave and return here
+	House  Vec2i   // inside the house, where eaten ghosts wait
+	Doors  []Vec2i // the house door, a wall to every actor
+	art    int     // board in the spritesheet, -1 to draw the walls instead
+	file   string  // where a custom board was loaded from or saved to
+}
+
+const (
+	NumBuiltinBoards = 6
+	MazeDir          = "mazes"
+	MazeExt          = ".maze"
+	MinMazeSize      = 5  // tiles in either direction
+	MaxMazeSize      = 64 // tiles, to keep the window on screen
+
+	// maze file characters besides the ones of Tile.String
+	mazeDoor   = '-'
+	mazeHouse  = 'H'
+	mazePlayer = 'P'
+	mazeGhost  = 'G'
+)
+
+// where things are on the built-in boards
+var (
+	DefaultPlayerStart = Vec2i{X: 13, Y: 23}
+	DefaultGhostStart  = Vec2i{X: 14, Y: 11}
+	DefaultHouse       = Vec2i{X: 14, Y: 14}
+	DefaultDoors       = []Vec2i{{X: 13, Y: 12}, {X: 14, Y: 12}}
+)
+
+// board returns the board being played.
+func (g *Game) board() *Board {
+	return g.boards[g.boardNum]
+}
+
+// loadBoards reads the built-in boards from the spritesheet and adds the
+// custom boards found in MazeDir.
+func (g *Game) loadBoards() {
+	g.boards = nil
+	for i := range NumBuiltinBoards {
+		g.boards = append(g.boards, g.readBoard(i))
+	}
+
+	custom, err := LoadBoards(MazeDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "custom mazes: %v\n", err)
+	}
+	g.boards = append(g.boards, custom...)
+}
+
+// readBoard maps a board of the spritesheet to tiles by comparing each 8x8
+// area with the dot and power pellet patterns.
+func (g *Game) readBoard(num int) *Board {
+	maze := NewMaze(GameWidth, GameHeight)
+	offset := num * GameHeight * Size
+	for y := 0; y < GameHeight; y++ {
+		for x := 0; x < GameWidth; x++ {
+			p, _ := g.readPixelArea(int32(x*Size), int32(y*Size+offset), Size, Size)
+			if p == 0 {
+				maze[y][x] = Empty
+			} else if p == DotMask { // dot
+				maze[y][x] = Dot
+			} else if p == PowerMask {
+				maze[y][x] = Power
+			} else {
+				maze[y][x] = Wall
+			}
+		}
+	}
+	markTunnels(maze)
+
+	return &Board{
+		Name:   fmt.Sprintf("board %d", num+1),
+		Maze:   maze,
+		Player: DefaultPlayerStart,
+		Ghost:  DefaultGhostStart,
+		House:  DefaultHouse,
+		Doors:  slices.Clone(DefaultDoors),
+		art:    num,
+	}
+}
+
+// Custom reports whether the board came from a maze file or the editor.
+func (b *Board) Custom() bool {
+	return b.art < 0
+}
+
+func (b *Board) Clone() *Board {
+	c := *b
+	c.Maze = b.Maze.Clone()
+	c.Doors = slices.Clone(b.Doors)
+	return &c
+}
+
+// GhostStart moves a behavior's starting tile, which is given for the
+// built-in boards, to the same place relative to this board's house.
+func (b *Board) GhostStart(start Vec2i) Vec2i {
+	t := start.Add(b.Ghost.X-DefaultGhostStart.X, b.Ghost.Y-DefaultGhostStart.Y)
+	if !b.Maze.IsValidMove(t) {
+		return b.Ghost
+	}
+	return t
+}
+
+// Corner moves a tile given for the built-in boards, such as a scatter
+// target, to the same distance from the nearest edges of this board.
+func (b *Board) Corner(t Vec2i) Vec2i {
+	if t.X >= GameWidth/2 {
+		t.X += b.Maze.Width() - GameWidth
+	}
+	if t.Y >= GameHeight/2 {
+		t.Y += b.Maze.Height() - GameHeight
+	}
+	return t
+}
+
+// IsDoor reports whether a tile is part of the house door.
+func (b *Board) IsDoor(t Vec2i) bool {
+	return slices.Contains(b.Doors, t)
+}
+
+// String encodes the board as a maze file: one line per row, one
+// character per tile.
+func (b *Board) String() string {
+	sb := strings.Builder{}
+	for y, row := range b.Maze {
+		for x, tile := range row {
+			t := Vec2i{X: x, Y: y}
+			switch {
+			case t == b.Player:
+				sb.WriteByte(mazePlayer)
+			case t == b.Ghost:
+				sb.WriteByte(mazeGhost)
+			case t == b.House:
+				sb.WriteByte(mazeHouse)
+			case b.IsDoor(t):
+				sb.WriteByte(mazeDoor)
+			default:
+				sb.WriteString(tile.String())
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ParseBoard reads a maze file. Walls are X, dots ., power pellets *,
+// tunnels @ and empty tiles spaces; P, G and H mark the player's start, the
+// ghosts' start above the house door and the inside of the house, and - is
+// the door. Lines starting with # are comments. The maze is as wide as its
+// longest line.
+func ParseBoard(name, text string) (*Board, error) {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	if len(lines) < MinMazeSize || len(lines) > MaxMazeSize {
+		return nil, fmt.Errorf("%s: %d rows, want %d to %d", name, len(lines), MinMazeSize, MaxMazeSize)
+	}
+	if width < MinMazeSize || width > MaxMazeSize {
+		return nil, fmt.Errorf("%s: %d columns, want %d to %d", name, width, MinMazeSize, MaxMazeSize)
+	}
+
+	b := &Board{Name: name, Maze: NewMaze(width, len(lines)), art: -1}
+	found := map[byte]bool{}
+	for y, line := range lines {
+		// editors may strip trailing spaces
+		line += strings.Repeat(" ", width-len(line))
+
+		for x := range width {
+			t := Vec2i{X: x, Y: y}
+			c := line[x]
+			tile := Empty
+			switch c {
+			case 'X':
+				tile = Wall
+			case '.':
+				tile = Dot
+			case '*':
+				tile = Power
+			case '@':
+				tile = Tunnel
+			case ' ':
+			case mazeDoor:
+				tile = Wall
+				b.Doors = append(b.Doors, t)
+			case mazePlayer, mazeGhost, mazeHouse:
+				if found[c] {
+					return nil, fmt.Errorf("%s:%d: more than one %c", name, y+1, c)
+				}
+				found[c] = true
+				switch c {
+				case mazePlayer:
+					b.Player = t
+				case mazeGhost:
+					b.Ghost = t
+				case mazeHouse:
+					b.House = t
+				}
+			default:
+				return nil, fmt.Errorf("%s:%d: unknown tile %q at column %d", name, y+1, c, x+1)
+			}
+			b.Maze[y][x] = tile
+		}
+	}
+
+	for _, c := range []byte{mazePlayer, mazeGhost, mazeHouse} {
+		if !found[c] {
+			return nil, fmt.Errorf("%s: no %c in the maze", name, c)
+		}
+	}
+	return b, nil
+}
+
+// LoadBoard reads a maze file, naming the board after the file, and
+// validates it. Boards with errors are rejected; warnings are logged.
+func LoadBoard(file string) (*Board, error) {
+	b, err := readBoardFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := ValidateBoard(b)
+	if err := BoardError(file, problems); err != nil {
+		return nil, err
+	}
+	for _, p := range problems {
+		gameLog.Warn(p.String(), "file", file)
+	}
+	return b, nil
+}
+
+func readBoardFile(file string) (*Board, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(file), MazeExt)
+	b, err := ParseBoard(name, string(data))
+	if err != nil {
+		return nil, err
+	}
+	b.file = file
+	return b, nil
+}
+
+// LoadBoards reads every maze file in a directory, skipping the ones that
+// fail to load. A missing directory is not an error.
+func LoadBoards(dir string) ([]*Board, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+MazeExt))
+	if err != nil {
+		return nil, err
+	}
+
+	var boards []*Board
+	var errs []string
+	for _, file := range files {
+		b, err := LoadBoard(file)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		boards = append(boards, b)
+	}
+	if len(errs) > 0 {
+		return boards, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return boards, nil
+}
+
+// Save writes the board to its maze file, picking a new file in MazeDir the
+// first time.
+func (b *Board) Save() error {
+	if b.file == "" {
+		if err := os.MkdirAll(MazeDir, 0o755); err != nil {
+			return err
+		}
+		for i := 1; ; i++ {
+			file := filepath.Join(MazeDir, fmt.Sprintf("custom-%d%s", i, MazeExt))
+			_, err := os.Stat(file)
+			if err == nil {
+				continue
+			}
+			if !os.IsNotExist(err) {
+				return err
+			}
+			b.file = file
+			b.Name = strings.TrimSuffix(filepath.Base(file), MazeExt)
+			break
+		}
+	}
+	return os.WriteFile(b.file, []byte(b.String()), 0o644)
+}
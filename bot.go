@@ -0,0 +1,181 @@
+package main
+
+import "math"
+
+// Bot is an autopilot Controller used for the attract mode demo and for
+// getting a baseline score when rules change. It plans with a breadth first
+// search over the MazeGraph toward the nearest dot, treating the tiles each
+// ghost is about to move through as blocked, and hunts frightened ghosts
+// while the power pellet lasts.
+type Bot struct {
+	// DangerSteps is how many tiles ahead each ghost's route is predicted.
+	DangerSteps int
+	// HuntTime is the minimum fright time (seconds) left to chase blue ghosts.
+	HuntTime float64
+}
+
+func NewBot() *Bot {
+	return &Bot{
+		DangerSteps: 6,
+		HuntTime:    1.0,
+	}
+}
+
+func (b *Bot) NextDirection(game *Game) Direction {
+	p := game.player
+
+	// direction changes are applied when the player reaches the next tile,
+	// so plan from there
+	from := p.tile
+	if p.vel.IsNonZero() {
+		from = game.maze.Wrap(from.Add(p.vel.X, p.vel.Y))
+	}
+	if !game.maze.IsValidMove(from) {
+		from = game.maze.Wrap(p.tile)
+	}
+
+	danger := b.dangerTiles(game)
+	hunting := game.frightTime-game.now() > b.HuntTime
+
+	isGoal := func(t Vec2i) bool {
+		if hunting {
+			for _, ghost := range game.ghosts {
+				if ghost.state == Frightened && ghost.tile == t {
+					return true
+				}
+			}
+		}
+		tile := game.maze[t.Y][t.X]
+		return tile == Dot || tile == Power
+	}
+
+	if dir := b.search(game.graph, from, danger, isGoal); dir != None {
+		return dir
+	}
+
+	return b.flee(game, from, danger)
+}
+
+// search runs a breadth first search from start and returns the first step
+// toward the closest tile accepted by isGoal, or None when no goal can be
+// reached without crossing a blocked tile.
+func (b *Bot) search(graph *MazeGraph, start Vec2i, blocked map[Vec2i]bool, isGoal func(Vec2i) bool) Direction {
+	first := map[Vec2i]Direction{start: None}
+	queue := []Vec2i{start}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur != start && isGoal(cur) {
+			return first[cur]
+		}
+
+		for _, dir := range graph.Directions(cur) {
+			next := graph.maze.Wrap(dir.GetNextTile(cur))
+			if _, seen := first[next]; seen || blocked[next] {
+				continue
+			}
+
+			if cur == start {
+				first[next] = dir
+			} else {
+				first[next] = first[cur]
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	return None
+}
+
+// flee picks the open neighbor of from that is furthest from any dangerous
+// ghost. Used when every route to a dot is cut off.
+func (b *Bot) flee(game *Game, from Vec2i, danger map[Vec2i]bool) Direction {
+	bestDir := None
+	bestDist := float32(-1)
+	for _, dir := range []Direction{Up, Left, Down, Right} {
+		next := game.maze.Wrap(dir.GetNextTile(from))
+		if !game.maze.IsValidMove(next) {
+			continue
+		}
+
+		dist := float32(math.MaxFloat32)
+		for _, ghost := range game.ghosts {
+			if isHarmless(ghost) {
+				continue
+			}
+			dist = min(dist, next.Distance(ghost.tile))
+		}
+		if danger[next] {
+			dist /= 4
+		}
+
+		if dist > bestDist {
+			bestDist = dist
+			bestDir = dir
+		}
+	}
+
+	return bestDir
+}
+
+// dangerTiles predicts the route of every dangerous ghost for DangerSteps
+// tiles using the same target each ghost's Behavior chose this frame.
+func (b *Bot) dangerTiles(game *Game) map[Vec2i]bool {
+	danger := make(map[Vec2i]bool)
+	for _, ghost := range game.ghosts {
+		if isHarmless(ghost) {
+			continue
+		}
+
+		for _, t := range predictGhost(game, ghost, b.DangerSteps) {
+			danger[t] = true
+		}
+
+		// a ghost can always reverse when the mode changes
+		danger[game.maze.Wrap(ghost.dir.Opposite().GetNextTile(ghost.tile))] = true
+	}
+
+	return danger
+}
+
+// predictGhost follows a ghost's targeting rules for the given number of
+// tiles, mirroring the decisions made by Ghost.ChooseDirection.
+func predictGhost(game *Game, g *Ghost, steps int) []Vec2i {
+	tile := game.maze.Wrap(g.tile)
+	dir := g.dir
+	path := []Vec2i{tile}
+
+	for i := 0; i < steps; i++ {
+		bestDir := None
+		minDist := float32(math.MaxFloat32)
+		for _, d := range []Direction{Up, Left, Down, Right} {
+			if d == dir.Opposite() {
+				continue
+			}
+			next := game.maze.Wrap(d.GetNextTile(tile))
+			if !game.maze.IsValidMove(next) {
+				continue
+			}
+			if dist := g.target.Distance(next); dist < minDist {
+				minDist = dist
+				bestDir = d
+			}
+		}
+
+		if bestDir == None {
+			break
+		}
+
+		dir = bestDir
+		tile = game.maze.Wrap(dir.GetNextTile(tile))
+		path = append(path, tile)
+	}
+
+	return path
+}
+
+func isHarmless(g *Ghost) bool {
+	return g.state == Frightened || g.state == Eaten || g.state == InHouse
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+// botGame puts the player on its start in testMaze with the dots of its row
+// eaten, so the nearest dots are 5 tiles away on either side.
+func botGame(t *testing.T, ghosts ...*Ghost) *Game {
+	t.Helper()
+	b := testBoard(t)
+	maze := b.Maze
+	for x := range maze[b.Player.Y] {
+		if maze[b.Player.Y][x] == Dot {
+			maze[b.Player.Y][x] = Empty
+		}
+	}
+	p := &Player{Entity: Entity{tile: b.Player}}
+	return &Game{maze: maze, graph: NewMazeGraph(maze), player: p, ghosts: ghosts}
+}
+
+func botGhost(tile Vec2i, dir Direction, state GhostState) *Ghost {
+	return &Ghost{Entity: Entity{tile: tile, dir: dir}, state: state, target: Vec2i{X: 5, Y: 5}}
+}
+
+func TestBot(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		ghosts []*Ghost
+		fright float64 // seconds of fright time left
+		edit   func(m Maze)
+		want   Direction
+	}{
+		{name: "ties go to the first direction", want: Left},
+		{
+			name: "nearest dot",
+			edit: func(m Maze) { m[4][9] = Dot; m[5][8] = Dot },
+			want: Right,
+		},
+		{
+			name:   "around a chasing ghost",
+			ghosts: []*Ghost{botGhost(Vec2i{X: 1, Y: 3}, Down, Chase)},
+			want:   Right,
+		},
+		{
+			name:   "flees a ghost that cuts off every dot",
+			ghosts: []*Ghost{botGhost(Vec2i{X: 8, Y: 5}, Left, Chase)},
+			want:   Left,
+		},
+		{
+			name:   "hunts a frightened ghost",
+			ghosts: []*Ghost{botGhost(Vec2i{X: 8, Y: 5}, Left, Frightened)},
+			fright: 5,
+			want:   Right,
+		},
+		{
+			name:   "leaves a ghost that is about to recover",
+			ghosts: []*Ghost{botGhost(Vec2i{X: 8, Y: 5}, Left, Frightened)},
+			fright: 0.5,
+			want:   Left,
+		},
+	} {
+		g := botGame(t, tt.ghosts...)
+		g.frightTime = tt.fright
+		if tt.edit != nil {
+			tt.edit(g.maze)
+		}
+		if got := NewBot().NextDirection(g); got != tt.want {
+			t.Errorf("%s: %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
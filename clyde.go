@@ -36,26 +36,19 @@ func (b Clyde) Sprite() Vec2i {
 	return Vec2i{X: 520, Y: 112}
 }
 
-func (b Clyde) Chase(game *Game) Vec2i {
-	// find self
-	for _, ghost := range game.ghosts {
-		if ghost.id == b.Id() {
-			d := game.player.tile.Distance(ghost.tile)
-			if d > 8 {
-				return game.player.tile
-			}
-			break
-		}
+func (b Clyde) Chase(game *Game, self *Ghost) Vec2i {
+	if game.player.tile.Distance(self.tile) > 8 {
+		return game.player.tile
 	}
 
-	return b.Scatter(game)
+	return b.Scatter(game, self)
 }
 
-func (b Clyde) Scatter(game *Game) Vec2i {
+func (b Clyde) Scatter(game *Game, _ *Ghost) Vec2i {
 	return Vec2i{X: 26, Y: 29} // depends on board
 }
 
-func (b Clyde) ExitHouse(game *Game) bool {
+func (b Clyde) ExitHouse(game *Game, _ *Ghost) bool {
 	if game.debug {
 		return game.levelTime > 3.0
 	}
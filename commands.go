@@ -0,0 +1,552 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Command is a debug command, run from the console or a startup script.
+// Complete, if set, returns the candidates for the argument being typed,
+// given the arguments before it.
+type Command struct {
+	Usage    string
+	Help     string
+	Run      func(g *Game, args []string) (string, error)
+	Complete func(g *Game, args []string) []string
+}
+
+// MaxStepTicks is the most ticks a step command runs, a minute of play, so
+// a typo can't hang the window.
+const MaxStepTicks = 60 * 60
+
+// commands is the registry of debug commands by name. It is filled in init
+// because help refers back to it.
+var commands map[string]Command
+
+func init() {
+	commands = map[string]Command{
+		"help":       {Usage: "help [command]", Help: "list commands or describe one", Run: cmdHelp, Complete: completeCommands},
+		"level":      {Usage: "level <n>", Help: "start level n", Run: cmdLevel},
+		"board":      {Usage: "board <n>", Help: "switch to board n, built-in boards are 0-5", Run: cmdBoard},
+		"teleport":   {Usage: "teleport <x> <y>", Help: "move the player to a tile", Run: cmdTeleport},
+		"ghost":      {Usage: "ghost <name> state <state>", Help: "force a ghost's state", Run: cmdGhost, Complete: completeGhost},
+		"mode":       {Usage: "mode <state>", Help: "force every ghost's state", Run: cmdMode, Complete: completeStates},
+		"god":        {Usage: "god", Help: "toggle invulnerability", Run: cmdGod},
+		"dots":       {Usage: "dots clear", Help: "eat every dot, clearing the level", Run: cmdDots, Complete: completeWords("clear")},
+		"speed":      {Usage: "speed <factor>", Help: "scale every actor's speed", Run: cmdSpeed},
+		"cornering":  {Usage: "cornering [pre post]", Help: "show or set how many pixels from a tile's center she may turn", Run: cmdCornering},
+		"time":       {Usage: "time <scale>", Help: "run the simulation slower or faster (0.25-4)", Run: cmdTime},
+		"step":       {Usage: "step [ticks]", Help: "pause and advance a number of ticks", Run: cmdStep},
+		"spawn":      {Usage: "spawn fruit", Help: "send in the level's fruit now", Run: cmdSpawn, Complete: completeWords("fruit")},
+		"seed":       {Usage: "seed <n>", Help: "reseed the random number generator", Run: cmdSeed},
+		"overlay":    {Usage: "overlay <name>", Help: "toggle a debug overlay", Run: cmdOverlay, Complete: completeOverlays},
+		"exec":       {Usage: "exec <file>", Help: "run the commands in a script file", Run: cmdExec},
+		"generate":   {Usage: "generate [seed]", Help: "play a newly generated board", Run: cmdGenerate},
+		"save":       {Usage: "save", Help: "save a custom or generated board to a maze file", Run: cmdSave},
+		"scale":      {Usage: "scale <n>", Help: "size the window in multiples of the arcade screen, 0 to fit", Run: cmdScale},
+		"rotate":     {Usage: "rotate", Help: "turn the picture a quarter, or back", Run: cmdRotate},
+		"fullscreen": {Usage: "fullscreen", Help: "toggle fullscreen", Run: cmdFullscreen},
+		"effects":    {Usage: "effects [none|effect,...]", Help: "show or set the post-processing chain", Run: cmdEffects, Complete: completeEffects},
+		"param":      {Usage: "param <effect.param> <value>", Help: "set a post-processing parameter", Run: cmdParam, Complete: completeParams},
+		"lang":       {Usage: "lang [language]", Help: "show or set the language of the game's text", Run: cmdLang, Complete: completeLang},
+		"scores":     {Usage: "scores [arcade|endless]", Help: "show a game mode's high scores", Run: cmdScores, Complete: completeWords("arcade", "endless")},
+	}
+}
+
+// CommandNames returns the registered command names, sorted.
+func CommandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RunCommand runs one command line and returns its output.
+func (g *Game) RunCommand(line string) (string, error) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown command %q, try help", args[0])
+	}
+	return cmd.Run(g, args[1:])
+}
+
+// RunScript runs a file of commands, one per line. Blank lines and lines
+// starting with # are skipped; the first failing command stops the script,
+// as does a script that execs itself, directly or through another.
+func (g *Game) RunScript(file string) error {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(g.scripts, path) {
+		return fmt.Errorf("%s: exec loop, already running", file)
+	}
+	g.scripts = append(g.scripts, path)
+	defer func() { g.scripts = g.scripts[:len(g.scripts)-1] }()
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out, err := g.RunCommand(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", file, n, err)
+		}
+		if out != "" {
+			gameLog.Info(out, "file", file, "line", n)
+		}
+	}
+	return scanner.Err()
+}
+
+// Seed restarts the simulation's random numbers from a seed.
+func (g *Game) Seed(seed uint64) {
+	g.rng = rand.New(rand.NewPCG(seed, seed))
+	g.seed = seed
+}
+
+func cmdHelp(_ *Game, args []string) (string, error) {
+	if len(args) > 0 {
+		cmd, ok := commands[args[0]]
+		if !ok {
+			return "", fmt.Errorf("unknown command %q", args[0])
+		}
+		return fmt.Sprintf("%s - %s", cmd.Usage, cmd.Help), nil
+	}
+	return strings.Join(CommandNames(), " "), nil
+}
+
+func cmdLevel(g *Game, args []string) (string, error) {
+	n, err := intArgs(args, 1)
+	if err != nil {
+		return "", err
+	}
+	if n[0] < 1 {
+		return "", fmt.Errorf("level must be at least 1")
+	}
+	g.level = n[0]
+	g.boardNum = g.levelBoard()
+	g.mapBoard()
+	g.resetActors()
+	return fmt.Sprintf("level %d on board %d", g.level, g.boardNum), nil
+}
+
+func cmdBoard(g *Game, args []string) (string, error) {
+	n, err := intArgs(args, 1)
+	if err != nil {
+		return "", err
+	}
+	if n[0] < 0 || n[0] >= len(g.boards) {
+		return "", fmt.Errorf("board must be 0-%d", len(g.boards)-1)
+	}
+	g.boardNum = n[0]
+	g.mapBoard()
+	g.resetActors()
+	return "", nil
+}
+
+func cmdTeleport(g *Game, args []string) (string, error) {
+	n, err := intArgs(args, 2)
+	if err != nil {
+		return "", err
+	}
+	tile := Vec2i{X: n[0], Y: n[1]}
+	if !g.maze.IsValidMove(tile) {
+		return "", fmt.Errorf("%s is a wall or outside the maze", tile)
+	}
+	g.player.teleport(tile)
+	return "", nil
+}
+
+func cmdGhost(g *Game, args []string) (string, error) {
+	if len(args) != 3 || args[1] != "state" {
+		return "", fmt.Errorf("usage: %s", commands["ghost"].Usage)
+	}
+	var ghost *Ghost
+	for _, e := range g.ghosts {
+		if strings.EqualFold(e.id.String(), args[0]) {
+			ghost = e
+		}
+	}
+	if ghost == nil {
+		return "", fmt.Errorf("no ghost %q in this game", args[0])
+	}
+	state, err := parseGhostState(args[2])
+	if err != nil {
+		return "", err
+	}
+	ghost.state = state
+	if state == Frightened {
+		g.frightTime = g.now() + g.rules.Preset.FrightTime()
+	}
+	return "", nil
+}
+
+func cmdMode(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: %s", commands["mode"].Usage)
+	}
+	state, err := parseGhostState(args[0])
+	if err != nil {
+		return "", err
+	}
+	g.setGhostMode(state)
+	return "", nil
+}
+
+func cmdGod(g *Game, _ []string) (string, error) {
+	g.god = !g.god
+	return fmt.Sprintf("god %t", g.god), nil
+}
+
+func cmdDots(g *Game, args []string) (string, error) {
+	if len(args) != 1 || args[0] != "clear" {
+		return "", fmt.Errorf("usage: %s", commands["dots"].Usage)
+	}
+	for y := range g.maze {
+		for x, tile := range g.maze[y] {
+			if tile == Dot || tile == Power {
+				g.maze[y][x] = Empty
+			}
+		}
+	}
+	g.dotsLeft = 0
+	return "", nil
+}
+
+func cmdSpeed(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: %s", commands["speed"].Usage)
+	}
+	f, err := strconv.ParseFloat(args[0], 32)
+	if err != nil || f <= 0 {
+		return "", fmt.Errorf("speed must be a positive number")
+	}
+	g.speedScale = float32(f)
+	return "", nil
+}
+
+func cmdCornering(g *Game, args []string) (string, error) {
+	switch len(args) {
+	case 0:
+	case 2:
+		var c [2]float64
+		for i, a := range args {
+			f, err := strconv.ParseFloat(a, 32)
+			if err != nil || f < 0 || f > MaxCornering {
+				return "", fmt.Errorf("cornering must be a number of pixels from 0 to %d", MaxCornering)
+			}
+			c[i] = f
+		}
+		g.rules.Cornering = Cornering{PreTurn: float32(c[0]), PostTurn: float32(c[1])}
+		g.player.cornering = g.rules.Cornering
+	default:
+		return "", fmt.Errorf("usage: %s", commands["cornering"].Usage)
+	}
+	return fmt.Sprintf("pre-turn %g, post-turn %g pixels", g.rules.Cornering.PreTurn, g.rules.Cornering.PostTurn), nil
+}
+
+func cmdTime(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: %s", commands["time"].Usage)
+	}
+	f, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || f <= 0 {
+		return "", fmt.Errorf("time scale must be a positive number")
+	}
+	g.setTimeScale(f)
+	return fmt.Sprintf("time scale %g", g.timeScale), nil
+}
+
+func cmdStep(g *Game, args []string) (string, error) {
+	n := []int{1}
+	if len(args) > 0 {
+		var err error
+		if n, err = intArgs(args, 1); err != nil {
+			return "", err
+		}
+	}
+	if n[0] < 1 || n[0] > MaxStepTicks {
+		return "", fmt.Errorf("ticks must be 1-%d", MaxStepTicks)
+	}
+	g.paused = true
+	for range n[0] {
+		g.step()
+	}
+	return fmt.Sprintf("tick %d", g.tick), nil
+}
+
+func cmdSpawn(g *Game, args []string) (string, error) {
+	if len(args) != 1 || args[0] != "fruit" {
+		return "", fmt.Errorf("usage: %s", commands["spawn"].Usage)
+	}
+	g.fruit = NewFruit(g)
+	if g.fruit == nil {
+		return "", fmt.Errorf("this board has no tunnels for the fruit")
+	}
+	g.emit(FruitSpawned{Fruit: g.fruit.kind, Tile: g.fruit.tile})
+	return g.fruit.kind.String(), nil
+}
+
+func cmdSeed(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: %s", commands["seed"].Usage)
+	}
+	seed, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return "", err
+	}
+	g.Seed(seed)
+	return "", nil
+}
+
+func cmdOverlay(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: %s", commands["overlay"].Usage)
+	}
+	if !g.overlays.Toggle(args[0]) {
+		return "", fmt.Errorf("unknown overlay %q", args[0])
+	}
+	g.debug = true
+	return "", nil
+}
+
+func cmdExec(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: %s", commands["exec"].Usage)
+	}
+	return "", g.RunScript(args[0])
+}
+
+func cmdGenerate(g *Game, args []string) (string, error) {
+	seed := g.rng.Uint64()
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		if seed, err = strconv.ParseUint(args[0], 10, 64); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("usage: %s", commands["generate"].Usage)
+	}
+
+	b, err := GenerateBoard(seed)
+	if err != nil {
+		return "", err
+	}
+	g.boards = append(g.boards, b)
+	g.boardNum = len(g.boards) - 1
+	g.mapBoard()
+	g.resetActors()
+	return fmt.Sprintf("%s is board %d", b.Name, g.boardNum), nil
+}
+
+func cmdSave(g *Game, _ []string) (string, error) {
+	b := g.board()
+	if !b.Custom() {
+		return "", fmt.Errorf("%s is built in", b.Name)
+	}
+	if err := b.Save(); err != nil {
+		return "", err
+	}
+	return "saved " + b.file, nil
+}
+
+func cmdScale(g *Game, args []string) (string, error) {
+	n, err := intArgs(args, 1)
+	if err != nil {
+		return "", err
+	}
+	if n[0] < 0 || n[0] > MaxScale {
+		return "", fmt.Errorf("scale must be 0-%d", MaxScale)
+	}
+	g.screen.SetScale(n[0])
+	return "", nil
+}
+
+func cmdRotate(g *Game, _ []string) (string, error) {
+	g.screen.SetRotated(!g.screen.rotated)
+	return "", nil
+}
+
+func cmdFullscreen(g *Game, _ []string) (string, error) {
+	g.screen.ToggleFullscreen()
+	return "", nil
+}
+
+func cmdEffects(g *Game, args []string) (string, error) {
+	post := g.screen.post
+	switch len(args) {
+	case 0:
+	case 1:
+		if err := post.Set(args[0]); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("usage: %s", commands["effects"].Usage)
+	}
+	return post.Describe(), nil
+}
+
+func cmdParam(g *Game, args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("usage: %s", commands["param"].Usage)
+	}
+	value, err := strconv.ParseFloat(args[1], 32)
+	if err != nil {
+		return "", err
+	}
+	return "", g.screen.post.SetParam(args[0], float32(value))
+}
+
+func cmdScores(g *Game, args []string) (string, error) {
+	mode := g.mode
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		if mode, err = ParseGameMode(args[0]); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("usage: %s", commands["scores"].Usage)
+	}
+	return g.scores.Table(mode), nil
+}
+
+func cmdLang(g *Game, args []string) (string, error) {
+	switch len(args) {
+	case 0:
+	case 1:
+		if err := g.setLanguage(args[0]); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("usage: %s", commands["lang"].Usage)
+	}
+	return fmt.Sprintf("language %s, have %s", g.catalog.Language, strings.Join(Languages(), ", ")), nil
+}
+
+// intArgs parses exactly n integer arguments.
+func intArgs(args []string, n int) ([]int, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("want %d numbers, got %d arguments", n, len(args))
+	}
+	result := make([]int, n)
+	for i, a := range args {
+		v, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", a)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// parseGhostState accepts a state's name with spaces left out or written
+// as underscores, e.g. in_house.
+func parseGhostState(s string) (GhostState, error) {
+	for state := Scatter; state <= LeavingHouse; state++ {
+		if stateWord(state) == strings.ToLower(s) {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown state %q", s)
+}
+
+func stateWord(state GhostState) string {
+	return strings.ReplaceAll(state.String(), " ", "_")
+}
+
+func completeCommands(_ *Game, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return CommandNames()
+}
+
+func completeWords(words ...string) func(*Game, []string) []string {
+	return func(_ *Game, args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		return words
+	}
+}
+
+func completeStates(_ *Game, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	var states []string
+	for state := Scatter; state <= LeavingHouse; state++ {
+		states = append(states, stateWord(state))
+	}
+	return states
+}
+
+func completeGhost(g *Game, args []string) []string {
+	switch len(args) {
+	case 0:
+		var names []string
+		for _, e := range g.ghosts {
+			names = append(names, strings.ToLower(e.id.String()))
+		}
+		return names
+	case 1:
+		return []string{"state"}
+	case 2:
+		return completeStates(g, nil)
+	}
+	return nil
+}
+
+func completeEffects(_ *Game, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return append([]string{"none"}, EffectNames()...)
+}
+
+func completeParams(_ *Game, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return EffectParamNames()
+}
+
+func completeLang(_ *Game, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return Languages()
+}
+
+func completeOverlays(_ *Game, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return OverlayNames()
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestStepRejectsBadCounts(t *testing.T) {
+	for _, n := range []int{0, -3, MaxStepTicks + 1} {
+		g := &Game{}
+		if _, err := cmdStep(g, []string{strconv.Itoa(n)}); err == nil {
+			t.Errorf("step %d: no error", n)
+		}
+		if g.paused || g.tick != 0 {
+			t.Errorf("step %d ran the game", n)
+		}
+	}
+}
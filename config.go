@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	ConfigFile = "config.json"
+	MaxLives   = 5
+)
+
+// Preset is a difficulty: how long the ghosts stay frightened and how fast
+// they move.
+type Preset int
+
+const (
+	PresetEasy Preset = iota
+	PresetNormal
+	PresetHard
+)
+
+func (p Preset) String() string {
+	switch p {
+	case PresetEasy:
+		return "easy"
+	case PresetNormal:
+		return "normal"
+	case PresetHard:
+		return "hard"
+	default:
+		panic("unhandled default case")
+	}
+}
+
+func ParsePreset(s string) (Preset, error) {
+	for _, p := range []Preset{PresetEasy, PresetNormal, PresetHard} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown difficulty %q, want easy, normal or hard", s)
+}
+
+// FrightTime is how long a power pellet frightens the ghosts, in seconds.
+func (p Preset) FrightTime() float64 {
+	return []float64{FrightDuration + 2, FrightDuration, FrightDuration - 2}[p]
+}
+
+// GhostSpeed multiplies the ghosts' speed.
+func (p Preset) GhostSpeed() float32 {
+	return []float32{0.9, 1, 1.1}[p]
+}
+
+// Rules are the settings that change how the game plays, like the arcade's
+// DIP switches.
+type Rules struct {
+	Preset    Preset
+	Lives     int  // at the start of a game
+	BonusLife int  // score that earns the extra life, 0 for none
+	ChaseBug  bool // Pinky and Inky aim up and left when she faces up
+	Cornering Cornering
+}
+
+// BonusLives are the arcade's choices of score for the extra life.
+var BonusLives = []int{ExtraLifeScore, 15000, 20000, 0}
+
+// Config is the settings kept between runs, in a JSON file next to the
+// input bindings. Command-line flags start out with its values, so a flag
+// overrides the file for one run; the settings menu changes the file.
+type Config struct {
+	file       string
+	Difficulty string    `json:"difficulty"`
+	Lives      int       `json:"lives"`
+	BonusLife  int       `json:"bonus_life"`
+	ChaseBug   bool      `json:"chase_bug"`
+	Cornering  Cornering `json:"cornering"`
+	Scale      int       `json:"scale"` // 0 to fit the monitor
+	Fullscreen bool      `json:"fullscreen"`
+	Rotate     bool      `json:"rotate"`
+	Effects    string    `json:"effects"`
+	Volume     float32   `json:"volume"`   // 0 to 1
+	Language   string    `json:"language"` // empty for the system's
+}
+
+// DefaultConfig returns the arcade's settings.
+func DefaultConfig() *Config {
+	return &Config{
+		Difficulty: PresetNormal.String(),
+		Lives:      StartingLives,
+		BonusLife:  ExtraLifeScore,
+		ChaseBug:   ChaseBug,
+		Cornering:  DefaultCornering,
+		Effects:    DefaultEffects,
+		Volume:     1,
+	}
+}
+
+// ConfigPath returns where the settings are kept.
+func ConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ConfigFile
+	}
+	return filepath.Join(dir, "mspackerfan", ConfigFile)
+}
+
+// LoadConfig reads the settings file. Settings missing from it keep their
+// defaults; a broken file is reported and then overwritten on the next
+// save.
+func LoadConfig(file string) *Config {
+	c := DefaultConfig()
+	c.file = file
+	if file == "" {
+		return c
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "loading settings: %v\n", err)
+		}
+		return c
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		fmt.Fprintf(os.Stderr, "loading settings: %v\n", err)
+	}
+	return c
+}
+
+// Save writes the settings file.
+func (c *Config) Save() error {
+	if c.file == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.file, data, 0o644)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	partial := DefaultConfig()
+	partial.Lives, partial.ChaseBug, partial.Language = 5, !ChaseBug, "de"
+
+	for _, tt := range []struct {
+		name string
+		data string // no file when empty
+		want *Config
+	}{
+		{name: "missing file", want: DefaultConfig()},
+		{name: "some settings", data: `{"lives": 5, "chase_bug": ` + strconv.FormatBool(!ChaseBug) + `, "language": "de"}`, want: partial},
+		{name: "broken file", data: `{"lives": 5,`, want: DefaultConfig()},
+	} {
+		file := filepath.Join(t.TempDir(), ConfigFile)
+		if tt.data != "" {
+			if err := os.WriteFile(file, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got := LoadConfig(file)
+		got.file = ""
+		if *got != *tt.want {
+			t.Errorf("%s: %+v, want %+v", tt.name, *got, *tt.want)
+		}
+	}
+}
+
+func TestConfigSaveLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "settings", ConfigFile)
+	c := LoadConfig(file)
+	c.Difficulty, c.Scale, c.Volume = PresetHard.String(), 3, 0.5
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := LoadConfig(file); *got != *c {
+		t.Errorf("loaded %+v, want %+v", *got, *c)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	ConsoleLines    = 12 // lines of output shown
+	ConsoleFontSize = 20
+	ConsoleHistory  = 100
+)
+
+// Console is a drop-down command line for the debug commands. The game is
+// paused while it is open. Up and down walk the history and tab completes
+// the word being typed.
+type Console struct {
+	open    bool
+	line    string
+	output  []string
+	history []string
+	histPos int // index into history while browsing, len(history) when not
+}
+
+func NewConsole() *Console {
+	return &Console{}
+}
+
+func (c *Console) Open() {
+	c.open = true
+	c.histPos = len(c.history)
+	// drop the key that opened the console
+	for rl.GetCharPressed() != 0 {
+	}
+}
+
+// Update handles typing while the console is open.
+func (c *Console) Update(g *Game) {
+	for ch := rl.GetCharPressed(); ch != 0; ch = rl.GetCharPressed() {
+		if ch == '`' || ch == '~' {
+			continue
+		}
+		c.line += string(ch)
+	}
+
+	switch {
+	case g.input.Pressed(ActionConsole):
+		c.open = false
+	case rl.IsKeyPressed(rl.KeyEnter):
+		c.submit(g)
+	case rl.IsKeyPressed(rl.KeyBackspace) || rl.IsKeyPressedRepeat(rl.KeyBackspace):
+		if len(c.line) > 0 {
+			c.line = c.line[:len(c.line)-1]
+		}
+	case rl.IsKeyPressed(rl.KeyUp):
+		if c.histPos > 0 {
+			c.histPos--
+			c.line = c.history[c.histPos]
+		}
+	case rl.IsKeyPressed(rl.KeyDown):
+		if c.histPos < len(c.history)-1 {
+			c.histPos++
+			c.line = c.history[c.histPos]
+		} else {
+			c.histPos = len(c.history)
+			c.line = ""
+		}
+	case rl.IsKeyPressed(rl.KeyTab):
+		c.complete(g)
+	}
+}
+
+func (c *Console) submit(g *Game) {
+	line := strings.TrimSpace(c.line)
+	c.line = ""
+	if line == "" {
+		return
+	}
+
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+		if len(c.history) > ConsoleHistory {
+			c.history = c.history[1:]
+		}
+	}
+	c.histPos = len(c.history)
+
+	c.print("> " + line)
+	out, err := g.RunCommand(line)
+	if err != nil {
+		c.print(err.Error())
+	} else if out != "" {
+		c.print(out)
+	}
+}
+
+// complete extends the word being typed to the longest prefix shared by
+// the matching candidates, and lists them when there is more than one.
+func (c *Console) complete(g *Game) {
+	words := strings.Fields(c.line)
+	if len(words) == 0 || strings.HasSuffix(c.line, " ") {
+		words = append(words, "")
+	}
+	prefix := words[len(words)-1]
+
+	var candidates []string
+	if len(words) == 1 {
+		candidates = CommandNames()
+	} else if cmd, ok := commands[words[0]]; ok && cmd.Complete != nil {
+		candidates = cmd.Complete(g, words[1:len(words)-1])
+	}
+
+	var matches []string
+	for _, cand := range candidates {
+		if strings.HasPrefix(cand, prefix) {
+			matches = append(matches, cand)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	common := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	c.line = c.line[:len(c.line)-len(prefix)] + common
+	if len(matches) == 1 {
+		c.line += " "
+	} else {
+		c.print(strings.Join(matches, " "))
+	}
+}
+
+func (c *Console) print(text string) {
+	c.output = append(c.output, strings.Split(text, "\n")...)
+	if len(c.output) > ConsoleLines {
+		c.output = c.output[len(c.output)-ConsoleLines:]
+	}
+}
+
+func (c *Console) Draw(g *Game) {
+	if !c.open {
+		return
+	}
+
+	w, _ := g.screenSize()
+	width := int32(w * Pixel)
+	lineHeight := int32(ConsoleFontSize + 4)
+	height := (ConsoleLines+1)*lineHeight + 8
+	rl.DrawRectangle(0, 0, width, height, rl.ColorAlpha(rl.Black, 0.85))
+	rl.DrawLine(0, height, width, height, rl.Green)
+
+	y := int32(4) + int32(ConsoleLines-len(c.output))*lineHeight
+	for _, line := range c.output {
+		rl.DrawText(line, 8, y, ConsoleFontSize, rl.LightGray)
+		y += lineHeight
+	}
+
+	cursor := ""
+	if int(rl.GetTime()*2)%2 == 0 {
+		cursor = "_"
+	}
+	rl.DrawText("] "+c.line+cursor, 8, y, ConsoleFontSize, rl.Green)
+}
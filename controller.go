@@ -0,0 +1,18 @@
+package main
+
+// Controller steers the player. Each frame the game asks the controller
+// which way the player wants to go next; None means "no change".
+// Implementations include the Human player and the autopilot Bot.
+type Controller interface {
+	NextDirection(game *Game) Direction
+}
+
+// Human steers the player with whatever keys, gamepad buttons or sticks are
+// bound to the direction actions.
+type Human struct {
+	input *Input
+}
+
+func (h Human) NextDirection(_ *Game) Direction {
+	return h.input.Direction()
+}
@@ -6,76 +6,211 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+const (
+	BlinkTime     = 0.25 // seconds 1UP is on, then off
+	MaxLifeIcons  = 5
+	MaxFruitIcons = 7
+)
+
+// Draw draws the game at the arcade's resolution, then the debug tools over
+// it at the window's, see Screen.
 func (g *Game) Draw() {
-	rl.ClearBackground(rl.Black)
+	g.screen.Begin()
+	if g.editor != nil {
+		g.editor.Draw(g)
+	} else {
+		g.drawGame()
+	}
+	if g.settings != nil {
+		g.settings.Draw(g)
+	}
+	if g.rebind != nil {
+		g.rebind.Draw(g)
+	}
 
-	rl.BeginMode2D(g.camera2)
-	g.drawBoard()
+	g.screen.Present()
+	if g.editor != nil {
+		g.editor.DrawTools(g)
+	} else {
+		g.drawTools()
+	}
+	g.console.Draw(g)
+	g.screen.End()
+}
 
-	// Animate characters
-	rl.BeginShaderMode(g.shader) // make background transparent
+func (g *Game) drawGame() {
+	g.screen.BeginView(g.camera2)
+	g.drawBoard()
 
-	g.drawGhosts() // draw player before behavior when player is eaten
-	g.drawPlayer()
+	// Animate characters, who wait off screen while PLAYER ONE shows
+	if g.phase != PhaseStart {
+		g.drawFruit()
+		g.drawGhosts() // draw player before behavior when player is eaten
+	}
+	if g.phase != PhaseStart && g.phase != PhaseGameOver {
+		g.drawPlayer()
+	}
 
-	rl.EndShaderMode()
-	rl.EndMode2D()
+	g.popups.Draw(g)
+	g.drawMessages()
+	g.screen.EndView()
 
 	g.drawLayout()
 }
 
+// drawTools draws the debug overlays and the time scale.
+func (g *Game) drawTools() {
+	g.screen.BeginView(g.camera2)
+	g.overlays.Draw(g)
+	g.screen.EndView()
+
+	g.drawTimeScale()
+	g.overlays.DrawLegend(g)
+}
+
+// screenSize is the size of the screen in tiles: the maze with room for the
+// score above and the lives below, and at least the arcade's screen.
+func (g *Game) screenSize() (int, int) {
+	w := max(g.maze.Width(), MinScreenWidth)
+	h := max(TopPadding+g.maze.Height()+BottomPadding, MinScreenHeight)
+	return w, h
+}
+
+// layout sizes the screen to the maze and centers the maze in it.
+func (g *Game) layout() {
+	w, h := g.screenSize()
+	g.camera2.Offset = rl.Vector2{
+		X: float32((w - g.maze.Width()) * Pixel / 2),
+		Y: TopPadding * Pixel,
+	}
+	if rl.IsWindowReady() {
+		g.screen.Resize(w, h)
+	}
+}
+
+// drawLayout draws the arcade's score lines above the maze and the lives
+// and fruit below it, or the credits when the game is over.
 func (g *Game) drawLayout() {
+	w, h := g.screenSize()
 	y := 0
 	pixelOffset := 8
-	g.drawText("1UP", 3, y, pixelOffset, rl.White)
-	g.drawText("HIGH SCORE", 9, y, pixelOffset, rl.White)
-	g.drawText("2UP", 22, y, pixelOffset, rl.White)
+	if g.phase == PhaseGameOver || int(g.now()/BlinkTime)%2 == 0 {
+		g.drawText(g.tr("1UP"), 3, y, pixelOffset, rl.White)
+	}
+	g.drawTextAligned(g.tr("HIGH SCORE"), float32(w*Pixel/2), float32(y*Pixel+pixelOffset), AlignCenter, rl.White)
+	g.drawText(g.tr("2UP"), w-6, y, pixelOffset, rl.White)
 
 	y += 1
 	pixelOffset += 4
+	// scores end under the labels, as on the arcade
+	top := float32(y*Pixel + pixelOffset)
 	// TODO player 1 vs 2
-	g.drawText(fmt.Sprintf("%d", g.player.score), 3, y, pixelOffset, rl.White) // player 1 score
-	g.drawText(fmt.Sprintf("%d", g.highScore), 13, y, pixelOffset, rl.White)   // high score
-	g.drawText("0", 24, y, pixelOffset, rl.White)                              // player 2 score
+	g.drawTextAligned(fmt.Sprintf("%d", g.player.score), 7*Pixel, top, AlignRight, rl.White)             // player 1 score
+	g.drawTextAligned(fmt.Sprintf("%d", g.highScore), float32((w/2+3)*Pixel), top, AlignRight, rl.White) // high score
+	g.drawTextAligned("0", float32((w-2)*Pixel), top, AlignRight, rl.White)                              // player 2 score
+
+	y = h - BottomPadding
+	if g.phase == PhaseGameOver {
+		g.drawText(fmt.Sprintf(g.tr("CREDIT %d"), g.credits), 2, y, pixelOffset, rl.White)
+		return
+	}
 
-	pixelOffset = 8
-	bottom := int32(ScreenHeight * Pixel)
-	msg := fmt.Sprintf("state: %s, dots: %d, time: %0.1f", g.ghosts[0].state, g.dotsEaten, g.levelTime)
-	rl.DrawText(msg, 5, bottom-50, 24, rl.Green)
-	rl.DrawFPS(10, 10)
-	//g.drawText(fmt.Sprintf("dots %d", g.dotsEaten), 19, 34, pixelOffset, rl.White) // player 2 score
+	// the lives in reserve, not the one being played
+	for i := range min(g.player.lives-1, MaxLifeIcons) {
+		g.drawIcon(g.player.sprite[Left], 2+i*2, y)
+	}
 
+	// a fruit for each level up to the seventh
+	fruits := min(g.level, MaxFruitIcons)
+	for i := range fruits {
+		g.drawIcon(FruitKind(i).Sprite(), w-2-(fruits-i)*2, y)
+	}
 }
 
-func (g *Game) drawBoard() {
+// drawMessages draws the arcade's text in the maze: PLAYER ONE over the
+// house and READY! or GAME OVER under it.
+func (g *Game) drawMessages() {
+	house := g.board().House
+	below := min(house.Y+3, g.maze.Height()-1)
+	switch g.phase {
+	case PhaseStart:
+		g.drawMazeText(g.tr("PLAYER ONE"), max(house.Y-3, 0), rl.NewColor(0, 255, 255, 255))
+		g.drawMazeText(g.tr("READY!"), below, rl.Yellow)
+	case PhaseReady:
+		g.drawMazeText(g.tr("READY!"), below, rl.Yellow)
+	case PhaseGameOver:
+		g.drawMazeText(g.tr("GAME OVER"), below, rl.Red)
+	}
+}
 
-	x := float32(GameWidth*Size) + Size/2
-	y := float32(g.boardNum * GameHeight * Size)
-	w := float32(GameWidth * Size)  // 28 * 8
-	h := float32(GameHeight * Size) // 31 * 8
-	src := rl.NewRectangle(x, y, w, h)
-	dst := rl.NewRectangle(0, 0, w*Zoom, h*Zoom)
+// drawMazeText draws text centered on a row of the maze.
+func (g *Game) drawMazeText(text string, y int, color rl.Color) {
+	g.drawTextAligned(text, float32(g.maze.Width()*Pixel/2), float32(y*Pixel), AlignCenter, color)
+}
 
+// drawIcon draws a 16x16 sprite two tiles wide at a tile of the screen.
+func (g *Game) drawIcon(loc Vec2i, x, y int) {
+	src := rl.NewRectangle(float32(loc.X), float32(loc.Y), 16, 16)
+	dst := rl.NewRectangle(float32(x*Pixel), float32(y*Pixel), 2*Pixel, 2*Pixel)
 	rl.DrawTexturePro(g.texture, src, dst, rl.Vector2{}, 0, rl.White)
+}
+
+// drawTimeScale shows when the simulation isn't running at normal speed.
+func (g *Game) drawTimeScale() {
+	var msg string
+	switch {
+	case g.paused:
+		msg = "PAUSED - STEP WITH " + bindingName(g.input.Binding(ActionStep))
+	case g.timeScale != 1:
+		msg = fmt.Sprintf("SPEED %gX", g.timeScale)
+	default:
+		return
+	}
+	w, _ := g.screenSize()
+	width := rl.MeasureText(msg, 24)
+	rl.DrawText(msg, int32(w*Pixel)-width-10, 10, 24, rl.Yellow)
+}
+
+func (g *Game) drawBoard() {
+	g.drawMaze(g.board(), g.maze)
+}
+
+// drawMaze draws a board's artwork, or its walls for a custom board, with
+// the dots of maze.
+func (g *Game) drawMaze(b *Board, maze Maze) {
+	art := b.art
+	if b.Custom() {
+		drawWalls(b, maze)
+		art = 0 // dot colors
+	} else {
+		x := float32(GameWidth*Size) + Size/2
+		y := float32(art * GameHeight * Size)
+		w := float32(GameWidth * Size)  // 28 * 8
+		h := float32(GameHeight * Size) // 31 * 8
+		src := rl.NewRectangle(x, y, w, h)
+		dst := rl.NewRectangle(0, 0, w*Zoom, h*Zoom)
+
+		rl.DrawTexturePro(g.texture, src, dst, rl.Vector2{}, 0, rl.White)
+	}
 
 	// source location from original artwork texture of a
 	// dot and power up so that the dots are the same
 	// color as you see in that image
 	var dotX, dotY, powerX, powerY float32 = 1, 1, 1, 2
 
-	if g.boardNum == 1 {
+	if art == 1 {
 		dotX, dotY = 1, 36
 		powerX, powerY = 1, 35
-	} else if g.boardNum == 2 {
+	} else if art == 2 {
 		dotX, dotY = 1, 63
 		powerX, powerY = 1, 65
-	} else if g.boardNum == 3 {
+	} else if art == 3 {
 		dotX, dotY = 1, 94
 		powerX, powerY = 1, 96
-	} else if g.boardNum == 4 {
+	} else if art == 4 {
 		dotX, dotY = 1, 125
 		powerX, powerY = 1, 127
-	} else if g.boardNum == 5 {
+	} else if art == 5 {
 		dotX, dotY = 1, 157
 		powerX, powerY = 1, 158
 	}
@@ -83,9 +218,8 @@ func (g *Game) drawBoard() {
 	dot := rl.NewRectangle(dotX*Size, dotY*Size, Size, Size)
 	power := rl.NewRectangle(powerX*Size, powerY*Size, Size, Size)
 
-	for y := 0; y < GameHeight; y++ {
-		for x := 0; x < GameWidth; x++ {
-			tile := g.maze[y][x]
+	for y, row := range maze {
+		for x, tile := range row {
 			if tile == Wall {
 				continue
 			}
@@ -101,11 +235,47 @@ func (g *Game) drawBoard() {
 			}
 		}
 	}
+}
 
-	if g.debugLayout {
-		g.drawCheckerBoard()
+// drawWalls outlines the walls of a custom board, which has no artwork.
+func drawWalls(b *Board, maze Maze) {
+	wall := rl.NewColor(33, 33, 222, 255)
+	for y := range maze {
+		for x, tile := range maze[y] {
+			t := Vec2i{X: x, Y: y}
+			rec := tileRect(t)
+			if b.IsDoor(t) {
+				rl.DrawRectangleRec(rl.NewRectangle(rec.X, rec.Y+Pixel/2-4, Pixel, 8), rl.Pink)
+				continue
+			}
+			if tile != Wall {
+				continue
+			}
+			rl.DrawRectangleRec(rec, rl.ColorAlpha(wall, 0.3))
+			// an edge wherever the wall meets a corridor
+			for _, d := range []Direction{Up, Left, Down, Right} {
+				n := d.GetNextTile(t)
+				if !maze.Contains(n) || maze[n.Y][n.X] == Wall {
+					continue
+				}
+				v := d.Vector()
+				edge := rl.NewRectangle(rec.X, rec.Y, Pixel, Pixel)
+				switch {
+				case v.X < 0:
+					edge.Width = 4
+				case v.X > 0:
+					edge.X += Pixel - 4
+					edge.Width = 4
+				case v.Y < 0:
+					edge.Height = 4
+				default:
+					edge.Y += Pixel - 4
+					edge.Height = 4
+				}
+				rl.DrawRectangleRec(edge, wall)
+			}
+		}
 	}
-
 }
 
 func (g *Game) drawGhosts() {
@@ -125,17 +295,18 @@ func (g *Game) drawGhosts() {
 
 		dst := rl.NewRectangle(e.pixel.X, e.pixel.Y, e.width*Zoom, e.height*Zoom)
 		rl.DrawTexturePro(g.texture, src, dst, rl.Vector2{}, 0, rl.White)
+	}
+}
 
-		if g.debug && e.tile.X != 0 && e.tile.Y != 0 {
-			f := float32(Pixel)
-			f2 := f / 2
-			target := e.target.Clamp()
-			rl.DrawCircle(int32(target.X*Pixel)+int32(f2), int32(target.Y*Pixel)+int32(f2), Pixel, rl.ColorAlpha(e.color, 0.5))
-			v1 := rl.Vector2{X: float32(target.X*Pixel) + f2, Y: float32(target.Y*Pixel) + f2}
-			v2 := rl.Vector2{X: e.pixel.X + f, Y: e.pixel.Y + f}
-			rl.DrawLineEx(v1, v2, 4, rl.ColorAlpha(e.color, 0.5))
-		}
+func (g *Game) drawFruit() {
+	f := g.fruit
+	if f == nil {
+		return
 	}
+	loc := f.kind.Sprite()
+	src := rl.NewRectangle(float32(loc.X), float32(loc.Y), f.width, f.height)
+	dst := rl.NewRectangle(f.pixel.X, f.pixel.Y+f.Bounce(), f.width*Zoom, f.height*Zoom)
+	rl.DrawTexturePro(g.texture, src, dst, rl.Vector2{}, 0, rl.White)
 }
 
 func (g *Game) drawPlayer() {
@@ -154,8 +325,8 @@ func (g *Game) drawCheckerBoard() {
 	i := 0
 	c1 := rl.Color{255, 255, 255, 120}
 	c2 := rl.Color{255, 255, 255, 80}
-	for y := 0; y < GameHeight; y++ {
-		for x := 0; x < GameWidth; x++ {
+	for y := range g.maze.Height() {
+		for x := range g.maze.Width() {
 			if y%10 == 0 {
 				g.drawText(fmt.Sprintf("%d", x%10), x, y, 0, rl.White)
 			} else if x == 0 {
@@ -175,16 +346,3 @@ func (g *Game) drawCheckerBoard() {
 		i++
 	}
 }
-
-func chromaShader() rl.Shader {
-	shader := rl.LoadShader("", "chroma_key.fs") // Empty string for vertex shader (use default)
-	// Set shader uniforms
-	keyColor := []float32{0.0, 0.0, 0.0} // Black (normalized RGB: 0.0 to 1.0)
-	threshold := []float32{0.05}         // Tolerance for slight color variations
-
-	keyColorLoc := rl.GetShaderLocation(shader, "keyColor")
-	thresholdLoc := rl.GetShaderLocation(shader, "threshold")
-	rl.SetShaderValue(shader, keyColorLoc, keyColor, rl.ShaderUniformVec3)
-	rl.SetShaderValue(shader, thresholdLoc, threshold, rl.ShaderUniformFloat)
-	return shader
-}
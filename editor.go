@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Brush is what the maze editor paints.
+type Brush int
+
+const (
+	BrushWall Brush = iota
+	BrushDot
+	BrushPower
+	BrushEmpty
+	BrushTunnel
+	BrushDoor
+	BrushHouse
+	BrushPlayer
+	BrushGhost
+
+	NumBrushes = int(BrushGhost) + 1
+)
+
+func (b Brush) String() string {
+	switch b {
+	case BrushWall:
+		return "wall"
+	case BrushDot:
+		return "dot"
+	case BrushPower:
+		return "power"
+	case BrushEmpty:
+		return "empty"
+	case BrushTunnel:
+		return "tunnel"
+	case BrushDoor:
+		return "door"
+	case BrushHouse:
+		return "house"
+	case BrushPlayer:
+		return "player"
+	case BrushGhost:
+		return "ghosts"
+	default:
+		panic("unhandled default case")
+	}
+}
+
+// Editor paints a copy of a board on the tile grid. The brush actions
+// (number keys) pick the brush, the left mouse button paints and the right
+// one erases, and the mirror action (M) mirrors painting onto the other half
+// of the maze. Play test (T) tries the board, save maze (S) writes it to a
+// maze file and the editor action leaves. The board is validated
+// after every change and the problems are marked in the maze.
+type Editor struct {
+	board    *Board
+	index    int // in Game.boards once play-tested or saved, -1 before
+	brush    Brush
+	mirror   bool
+	problems []Problem
+	message  string
+}
+
+// NewEditor starts editing a copy of a board. Built-in boards are copied
+// into a new custom board; custom boards are edited in place.
+func NewEditor(g *Game) *Editor {
+	e := &Editor{board: g.board().Clone(), index: -1, mirror: true}
+	if e.board.Custom() {
+		e.index = g.boardNum
+	} else {
+		e.board.art = -1
+		e.board.file = ""
+		e.board.Name = "custom"
+	}
+	e.validate()
+	return e
+}
+
+func (e *Editor) Update(g *Game) {
+	for i := range NumBrushes {
+		if g.input.Pressed(ActionBrush + Action(i)) {
+			e.brush = Brush(i)
+		}
+	}
+
+	switch {
+	case g.input.Pressed(ActionEditor):
+		g.editor = nil
+		return
+	case g.input.Pressed(ActionMirror):
+		e.mirror = !e.mirror
+	case g.input.Pressed(ActionPlayTest):
+		e.playTest(g)
+		return
+	case g.input.Pressed(ActionSaveMaze):
+		e.save(g)
+	}
+
+	t, ok := g.mouseTile()
+	if !ok {
+		return
+	}
+	if rl.IsMouseButtonDown(rl.MouseButtonLeft) {
+		e.paint(t, e.brush)
+	} else if rl.IsMouseButtonDown(rl.MouseButtonRight) {
+		e.paint(t, BrushEmpty)
+	}
+}
+
+func (e *Editor) paint(t Vec2i, brush Brush) {
+	before := e.board.String()
+
+	e.paintTile(t, brush)
+	// spawn points are single tiles, everything else can be mirrored
+	if e.mirror && brush != BrushPlayer && brush != BrushGhost && brush != BrushHouse {
+		e.paintTile(Vec2i{X: e.board.Maze.Width() - 1 - t.X, Y: t.Y}, brush)
+	}
+
+	if e.board.String() != before {
+		e.message = ""
+		e.validate()
+	}
+}
+
+func (e *Editor) paintTile(t Vec2i, brush Brush) {
+	b := e.board
+	b.Doors = slices.DeleteFunc(b.Doors, func(d Vec2i) bool { return d == t })
+
+	tile := Empty
+	switch brush {
+	case BrushWall:
+		tile = Wall
+	case BrushDot:
+		tile = Dot
+	case BrushPower:
+		tile = Power
+	case BrushTunnel:
+		tile = Tunnel
+	case BrushDoor:
+		tile = Wall
+		b.Doors = append(b.Doors, t)
+	case BrushHouse:
+		b.House = t
+	case BrushPlayer:
+		b.Player = t
+	case BrushGhost:
+		b.Ghost = t
+	}
+	b.Maze[t.Y][t.X] = tile
+}
+
+func (e *Editor) validate() {
+	e.problems = ValidateBoard(e.board)
+}
+
+// playTest puts the board in the game's list and starts playing it.
+func (e *Editor) playTest(g *Game) {
+	if BoardError(e.board.Name, e.problems) != nil {
+		e.message = "fix the errors before playing"
+		return
+	}
+
+	e.store(g)
+	g.boardNum = e.index
+	g.mapBoard()
+	g.resetActors()
+	g.editor = nil
+}
+
+func (e *Editor) save(g *Game) {
+	if err := e.board.Save(); err != nil {
+		e.message = err.Error()
+		return
+	}
+	e.store(g)
+	e.message = "saved " + e.board.file
+}
+
+// store adds the board to the game's boards, or updates it once added.
+func (e *Editor) store(g *Game) {
+	board := e.board.Clone()
+	if e.index < 0 {
+		g.boards = append(g.boards, board)
+		e.index = len(g.boards) - 1
+	} else {
+		g.boards[e.index] = board
+	}
+}
+
+// Draw draws the board being edited in place of the game.
+func (e *Editor) Draw(g *Game) {
+	g.screen.BeginView(g.camera2)
+	g.drawMaze(e.board, e.board.Maze)
+	g.drawCheckerBoard()
+	g.screen.EndView()
+}
+
+// DrawTools marks the spawns, the problems and the tile under the mouse in
+// the maze, and shows the brushes and problems above and below it.
+func (e *Editor) DrawTools(g *Game) {
+	g.screen.BeginView(g.camera2)
+	b := e.board
+	for _, marker := range []struct {
+		tile  Vec2i
+		color rl.Color
+		label string
+	}{{b.Player, rl.Yellow, "P"}, {b.Ghost, rl.Red, "G"}, {b.House, rl.Pink, "H"}} {
+		c := tileCenter(marker.tile)
+		rl.DrawCircleV(c, Pixel/2-2, marker.color)
+		rl.DrawText(marker.label, int32(c.X)-6, int32(c.Y)-10, 20, rl.Black)
+	}
+	for _, p := range e.problems {
+		color := rl.Red
+		if p.Warning {
+			color = rl.Orange
+		}
+		if b.Maze.Contains(p.Tile) {
+			rl.DrawRectangleLinesEx(tileRect(p.Tile), 3, color)
+		}
+	}
+	if t, ok := g.mouseTile(); ok {
+		rl.DrawRectangleLinesEx(tileRect(t), 2, rl.White)
+	}
+	g.screen.EndView()
+
+	var brushes []string
+	for i := range NumBrushes {
+		name := fmt.Sprintf("%s %s", bindingName(g.input.Binding(ActionBrush+Action(i))), Brush(i))
+		if Brush(i) == e.brush {
+			name = "[" + name + "]"
+		}
+		brushes = append(brushes, name)
+	}
+	mirror := "off"
+	if e.mirror {
+		mirror = "on"
+	}
+	w, h := g.screenSize()
+	rl.DrawRectangle(0, 0, int32(w*Pixel), TopPadding*Pixel, rl.Black)
+	rl.DrawText("EDITING "+strings.ToUpper(b.Name), 10, 4, 24, rl.Yellow)
+	rl.DrawText(strings.Join(brushes, "  "), 10, 34, 20, rl.White)
+	in := g.input
+	rl.DrawText(fmt.Sprintf("%s mirror %s   %s play   %s save   %s exit",
+		bindingName(in.Binding(ActionMirror)), mirror, bindingName(in.Binding(ActionPlayTest)),
+		bindingName(in.Binding(ActionSaveMaze)), bindingName(in.Binding(ActionEditor))), 10, 60, 20, rl.Gray)
+
+	bottom := int32((TopPadding + b.Maze.Height()) * Pixel)
+	rl.DrawRectangle(0, bottom, int32(w*Pixel), int32(h*Pixel)-bottom, rl.Black)
+	status, color := "board is valid", rl.Green
+	if len(e.problems) > 0 {
+		status = fmt.Sprintf("%d problems, first %s", len(e.problems), e.problems[0])
+		color = rl.Red
+		if BoardError(b.Name, e.problems) == nil {
+			color = rl.Orange
+		}
+	}
+	if e.message != "" {
+		status = e.message
+	}
+	rl.DrawText(status, 10, bottom+8, 20, color)
+}
@@ -29,6 +29,7 @@ type Entity struct {
 	tile        Vec2i
 	pixel       rl.Vector2
 	pixelsMoved float32
+	corner      rl.Vector2 // offset left over from cutting a corner, in pixels
 	width       float32
 	height      float32
 	frameCount  int
@@ -125,36 +126,37 @@ func (v Vec2i) IsNonZero() bool {
 	return v.X != 0 || v.Y != 0
 }
 
-func (v Vec2i) InMaze() bool {
-	return v.X >= 0 && v.X < GameWidth && v.Y >= 0 && v.Y < GameHeight
-}
-
-func (v Vec2i) Clamp() Vec2i {
-	if v.X < 0 {
-		v.X = 0
-	} else if v.X > GameWidth-1 {
-		v.X = GameWidth - 1
-	}
-
-	if v.Y < 0 {
-		v.Y = 0
-	} else if v.Y > GameHeight-1 {
-		v.Y = GameHeight - 1
-	}
-
-	return v
-}
-
 func (e *Entity) move(speed float32) {
 	if e.vel.X != 0 || e.vel.Y != 0 {
 		e.pixelsMoved += speed
 
+		// while cornering, move diagonally back onto the new lane
+		e.corner.X = approachZero(e.corner.X, speed)
+		e.corner.Y = approachZero(e.corner.Y, speed)
+
 		clampedPixelsMoved := float32(math.Min(float64(e.pixelsMoved), float64(Size)))
 		visualOffsetX := float32(e.vel.X) * clampedPixelsMoved
 		visualOffsetY := float32(e.vel.Y) * clampedPixelsMoved
 
 		//. tile size or zoom
-		e.pixel.X = (float32(e.tile.X*Size) + visualOffsetX - Size/2) * Zoom
-		e.pixel.Y = (float32(e.tile.Y*Size) + visualOffsetY - Size/2) * Zoom
+		e.pixel.X = (float32(e.tile.X*Size) + visualOffsetX + e.corner.X - Size/2) * Zoom
+		e.pixel.Y = (float32(e.tile.Y*Size) + visualOffsetY + e.corner.Y - Size/2) * Zoom
+	}
+}
+
+// teleport puts the entity on a tile, standing still.
+func (e *Entity) teleport(tile Vec2i) {
+	e.tile = tile
+	e.vel = Vec2i{}
+	e.pixelsMoved = 0
+	e.corner = rl.Vector2{}
+	e.pixel.X = float32(tile.X*Size-Size/2) * Zoom
+	e.pixel.Y = float32(tile.Y*Size-Size/2) * Zoom
+}
+
+func approachZero(v, step float32) float32 {
+	if v > 0 {
+		return max(v-step, 0)
 	}
+	return min(v+step, 0)
 }
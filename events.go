@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Event is something that happened in the simulation during a tick. Game
+// logic only emits events; subscribers on the EventBus (audio, score popups,
+// stats, the event log and the replay recorder) react to them, so the rules
+// never call presentation code directly.
+type Event interface {
+	String() string
+}
+
+type GameStarted struct{}
+
+type DotEaten struct {
+	Tile Vec2i
+}
+
+type PelletEaten struct {
+	Tile Vec2i
+}
+
+type GhostEaten struct {
+	Ghost  *Ghost
+	Tile   Vec2i
+	Points int
+}
+
+type PlayerDied struct {
+	Ghost *Ghost // who caught her
+	Tile  Vec2i
+}
+
+type ExtraLife struct {
+	Lives int
+}
+
+type ModeChanged struct {
+	Ghost    *Ghost
+	From, To GhostState
+}
+
+type GhostLeftHouse struct {
+	Ghost *Ghost
+}
+
+type FruitSpawned struct {
+	Fruit FruitKind
+	Tile  Vec2i
+}
+
+type FruitEaten struct {
+	Fruit  FruitKind
+	Tile   Vec2i
+	Points int
+}
+
+type LevelCleared struct {
+	Level int
+}
+
+// GameOver is emitted when the player has lost her last life.
+type GameOver struct {
+	Score int
+	Level int
+}
+
+// PlayerTurned is emitted when the player's controller asks for a new
+// direction. Recording these is enough to replay a game.
+type PlayerTurned struct {
+	Dir  Direction
+	Tick int // tick the controller was asked on
+}
+
+func (e GameStarted) String() string { return "game started" }
+func (e DotEaten) String() string    { return fmt.Sprintf("dot eaten at %s", e.Tile) }
+func (e PelletEaten) String() string { return fmt.Sprintf("power pellet eaten at %s", e.Tile) }
+func (e GhostEaten) String() string {
+	return fmt.Sprintf("player eats %s at %s for %d", e.Ghost.name, e.Tile, e.Points)
+}
+func (e PlayerDied) String() string {
+	return fmt.Sprintf("ghost %s eats player at %s", e.Ghost.name, e.Tile)
+}
+func (e ExtraLife) String() string { return fmt.Sprintf("extra life, %d lives", e.Lives) }
+func (e ModeChanged) String() string {
+	return fmt.Sprintf("%s from %s to %s", e.Ghost.name, e.From, e.To)
+}
+func (e GhostLeftHouse) String() string { return fmt.Sprintf("%s left the house", e.Ghost.name) }
+func (e FruitSpawned) String() string {
+	return fmt.Sprintf("%s spawned at %s", e.Fruit, e.Tile)
+}
+func (e FruitEaten) String() string {
+	return fmt.Sprintf("player eats %s at %s for %d", e.Fruit, e.Tile, e.Points)
+}
+func (e LevelCleared) String() string { return fmt.Sprintf("level %d cleared", e.Level) }
+func (e GameOver) String() string {
+	return fmt.Sprintf("game over on level %d with %d", e.Level, e.Score)
+}
+func (e PlayerTurned) String() string { return fmt.Sprintf("player turned %s", e.Dir) }
+
+// EventName returns the type name of an event, e.g. "DotEaten".
+func EventName(e Event) string {
+	return reflect.TypeOf(e).Name()
+}
+
+// Subscriber receives every event emitted during a tick.
+type Subscriber interface {
+	HandleEvent(g *Game, tick int, e Event)
+}
+
+// SubscriberFunc lets a plain function subscribe to the EventBus.
+type SubscriberFunc func(g *Game, tick int, e Event)
+
+func (f SubscriberFunc) HandleEvent(g *Game, tick int, e Event) {
+	f(g, tick, e)
+}
+
+// EventBus queues the events emitted during a tick and hands them to the
+// subscribers, in order, once the tick is over.
+type EventBus struct {
+	events      []Event
+	subscribers []Subscriber
+}
+
+func (b *EventBus) Subscribe(s Subscriber) {
+	b.subscribers = append(b.subscribers, s)
+}
+
+func (b *EventBus) Emit(e Event) {
+	b.events = append(b.events, e)
+}
+
+// Flush delivers the queued events to every subscriber and clears the queue.
+func (b *EventBus) Flush(g *Game) {
+	for _, e := range b.events {
+		for _, s := range b.subscribers {
+			s.HandleEvent(g, g.tick, e)
+		}
+	}
+	b.events = b.events[:0]
+}
+
+// emit queues an event for the end of this tick.
+func (g *Game) emit(e Event) {
+	g.bus.Emit(e)
+}
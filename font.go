@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode"
+	"unicode/utf8"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const FontFile = "font.json"
+
+// FontDesc describes a bitmap font: an image cut into square cells, with the
+// glyph in each cell listed row by row, left to right, and a space for a
+// cell left empty. Glyphs are a cell wide unless Widths says otherwise,
+// which makes a proportional font.
+type FontDesc struct {
+	Image  string         `json:"image"` // relative to the description
+	Cell   int            `json:"cell"`  // pixels
+	Rows   []string       `json:"rows"`
+	Widths map[string]int `json:"widths"` // pixels, by glyph
+	Space  int            `json:"space"`  // width of a space, a cell if 0
+}
+
+// Glyph is where a character is in a font's image.
+type Glyph struct {
+	Src     rl.Rectangle
+	Advance float32 // image pixels to the next glyph
+	accent  *Accent // drawn with the glyph, for an accented letter
+}
+
+// Font draws text from a bitmap. Characters it doesn't have are drawn
+// upper case if it has those, accented letters as the letter and an
+// accent, then anything else from the fallback font.
+type Font struct {
+	texture  rl.Texture2D
+	height   float32 // image pixels of a line
+	space    float32
+	glyphs   map[rune]Glyph
+	fallback *Font
+	owned    bool // texture is unloaded on Close
+}
+
+// LoadFont reads a font description and its image, with raylib's built-in
+// font as the fallback.
+func LoadFont(file string) (*Font, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var desc FontDesc
+	if err := json.Unmarshal(data, &desc); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if desc.Cell <= 0 {
+		return nil, fmt.Errorf("%s: cell size must be positive", file)
+	}
+
+	image := filepath.Join(filepath.Dir(file), desc.Image)
+	if _, err := os.Stat(image); err != nil {
+		return nil, err
+	}
+
+	cell := float32(desc.Cell)
+	f := &Font{height: cell, space: cell, glyphs: map[rune]Glyph{}, fallback: DefaultFont()}
+	if desc.Space > 0 {
+		f.space = float32(desc.Space)
+	}
+	for y, row := range desc.Rows {
+		for x, r := range []rune(row) {
+			if r != ' ' {
+				f.glyphs[r] = Glyph{Src: rl.NewRectangle(float32(x)*cell, float32(y)*cell, cell, cell), Advance: cell}
+			}
+		}
+	}
+	for s, width := range desc.Widths {
+		r, n := utf8.DecodeRuneInString(s)
+		g, ok := f.glyphs[r]
+		if n != len(s) || !ok {
+			return nil, fmt.Errorf("%s: width for %q, which isn't a glyph", file, s)
+		}
+		g.Src.Width = float32(width)
+		g.Advance = float32(width)
+		f.glyphs[r] = g
+	}
+
+	f.texture = rl.LoadTexture(image)
+	f.owned = true
+	return f, nil
+}
+
+// DefaultFont is raylib's built-in font, proportional and covering Latin-1,
+// for when there is no font image and for the glyphs one lacks.
+func DefaultFont() *Font {
+	font := rl.GetFontDefault()
+	f := &Font{texture: font.Texture, height: float32(font.BaseSize), glyphs: map[rune]Glyph{}}
+	for r := rune(33); r < 256; r++ {
+		info := rl.GetGlyphInfo(font, r)
+		if info.Value != r {
+			continue
+		}
+		src := rl.GetGlyphAtlasRec(font, r)
+		advance := float32(info.AdvanceX)
+		if advance == 0 {
+			advance = src.Width
+		}
+		f.glyphs[r] = Glyph{Src: src, Advance: advance + 1} // raylib's spacing
+	}
+	f.space = f.height / 2
+	return f
+}
+
+// Close unloads the font's image.
+func (f *Font) Close() {
+	if f.owned {
+		rl.UnloadTexture(f.texture)
+	}
+}
+
+// glyph finds a character, returning the font that has it.
+func (f *Font) glyph(r rune) (Glyph, *Font, bool) {
+	if g, ok := f.letter(r); ok {
+		return g, f, true
+	}
+	if a, ok := accentedLetters[r]; ok {
+		if g, ok := f.letter(a.base); ok {
+			g.accent = a.accent
+			return g, f, true
+		}
+	}
+	if f.fallback != nil {
+		return f.fallback.glyph(r)
+	}
+	return Glyph{}, nil, false
+}
+
+// letter finds a character in the font's own image, or its upper case.
+func (f *Font) letter(r rune) (Glyph, bool) {
+	if g, ok := f.glyphs[r]; ok {
+		return g, true
+	}
+	g, ok := f.glyphs[unicode.ToUpper(r)]
+	return g, ok
+}
+
+// Measure returns the width of text drawn size screen units high.
+func (f *Font) Measure(text string, size float32) float32 {
+	var width float32
+	for _, r := range text {
+		width += f.advance(r, size)
+	}
+	return width
+}
+
+// advance is how far a character moves the next one along at a size.
+func (f *Font) advance(r rune, size float32) float32 {
+	g, from, ok := f.glyph(r)
+	if r == ' ' || !ok {
+		return f.space * size / f.height
+	}
+	return g.Advance * size / from.height
+}
+
+// Draw draws text with its top left corner at pos, size screen units high.
+func (f *Font) Draw(text string, pos rl.Vector2, size float32, color rl.Color) {
+	for _, r := range text {
+		if g, from, ok := f.glyph(r); ok && r != ' ' {
+			scale := size / from.height
+			dst := rl.NewRectangle(pos.X, pos.Y, g.Src.Width*scale, g.Src.Height*scale)
+			rl.DrawTexturePro(from.texture, g.Src, dst, rl.Vector2{}, 0, color)
+			if g.accent != nil {
+				g.accent.draw(g, pos, from.height, scale, color)
+			}
+		}
+		pos.X += f.advance(r, size)
+	}
+}
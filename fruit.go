@@ -0,0 +1,148 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type FruitKind int
+
+const (
+	Cherry FruitKind = iota
+	Strawberry
+	Orange
+	Pretzel
+	Apple
+	Pear
+	Banana
+
+	FruitSpeed = 0.5 // fraction of the player's speed
+)
+
+// FruitDots are the dot counts at which a fruit enters the maze.
+var FruitDots = []int{64, 176}
+
+func (f FruitKind) String() string {
+	switch f {
+	case Cherry:
+		return "cherry"
+	case Strawberry:
+		return "strawberry"
+	case Orange:
+		return "orange"
+	case Pretzel:
+		return "pretzel"
+	case Apple:
+		return "apple"
+	case Pear:
+		return "pear"
+	case Banana:
+		return "banana"
+	default:
+		panic("unhandled default case")
+	}
+}
+
+func (f FruitKind) Points() int {
+	return []int{100, 200, 500, 700, 1000, 2000, 5000}[f]
+}
+
+// Sprite is the location of the fruit in the spritesheet.
+func (f FruitKind) Sprite() Vec2i {
+	return Vec2i{X: 504 + int(f)*16, Y: 0}
+}
+
+// fruitForLevel returns the fruit of a level. From level 8 on the fruit is
+// picked at random.
+func fruitForLevel(game *Game) FruitKind {
+	level := game.level
+	if level < 1 {
+		level = 1
+	}
+	if level <= int(Banana)+1 {
+		return FruitKind(level - 1)
+	}
+	return FruitKind(game.rng.IntN(int(Banana) + 1))
+}
+
+// Fruit enters the maze through a tunnel, bounces along a route through
+// the middle of the maze and leaves through another tunnel.
+type Fruit struct {
+	Entity
+	kind  FruitKind
+	route []Vec2i // tiles still to visit
+}
+
+// NewFruit plans the fruit's route with the MazeGraph. Returns nil when the
+// board has no tunnels to enter through.
+func NewFruit(game *Game) *Fruit {
+	var tunnels []Vec2i
+	for y := range game.maze {
+		for x := range game.maze[y] {
+			if game.maze[y][x] == Tunnel {
+				tunnels = append(tunnels, Vec2i{X: x, Y: y})
+			}
+		}
+	}
+	if len(tunnels) == 0 {
+		return nil
+	}
+
+	entry := tunnels[game.rng.IntN(len(tunnels))]
+	exit := tunnels[game.rng.IntN(len(tunnels))]
+	nodes := game.graph.Nodes()
+	middle := nodes[game.rng.IntN(len(nodes))]
+
+	route := append(game.graph.Path(entry, middle), game.graph.Path(middle, exit)...)
+	if len(route) == 0 {
+		return nil
+	}
+
+	kind := fruitForLevel(game)
+	return &Fruit{
+		Entity: Entity{
+			name:   kind.String(),
+			tile:   entry,
+			pixel:  rl.Vector2{X: float32(entry.X * Pixel), Y: float32(entry.Y * Pixel)},
+			width:  16,
+			height: 16,
+		},
+		kind:  kind,
+		route: route,
+	}
+}
+
+// Update moves the fruit one frame along its route and reports whether it
+// is still in the maze.
+func (f *Fruit) Update(game *Game) bool {
+	if f.pixelsMoved >= Size || f.vel.IsZero() {
+		if f.vel.IsNonZero() {
+			f.tile = game.maze.Wrap(f.tile.Add(f.vel.X, f.vel.Y))
+		}
+		f.pixelsMoved = 0
+
+		if len(f.route) == 0 {
+			return false
+		}
+
+		next := f.route[0]
+		f.route = f.route[1:]
+		f.vel = Vec2i{X: next.X - f.tile.X, Y: next.Y - f.tile.Y}
+		// the route wraps through a tunnel
+		if f.vel.X > 1 {
+			f.vel.X = -1
+		} else if f.vel.X < -1 {
+			f.vel.X = 1
+		}
+	}
+
+	f.frameCount++
+	f.move(playerSpeed(game.difficulty()) * FruitSpeed * game.speedScale)
+	return true
+}
+
+// Bounce is the vertical offset, in pixels, of the fruit's hop.
+func (f *Fruit) Bounce() float32 {
+	return float32(math.Abs(math.Sin(float64(f.frameCount)*math.Pi/16))) * -2 * Zoom
+}
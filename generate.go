@@ -0,0 +1,293 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// The generator lays corridors along the arcade's corridor lines: a lattice
+// of junctions on the left half of the maze, joined by straight corridors,
+// which is mirrored onto the right half. The lines are at least three tiles
+// apart, so corridors are one tile wide with walls at least two thick.
+var (
+	genColumns = []int{1, 6, 9, 12}
+	genRows    = []int{1, 5, 8, 11, 14, 17, 20, 23, 26, 29}
+	// rows with a tunnel to the other side, picked as a set
+	genTunnels = [][]int{{14}, {8}, {20}, {8, 20}}
+	// the corridor around the house, and its corners and middle junctions
+	genRing      = rect{Min: Vec2i{X: 9, Y: 11}, Max: Vec2i{X: GameWidth - 10, Y: 17}}
+	genRingNodes = []Vec2i{{X: 9, Y: 11}, {X: 12, Y: 11}, {X: 9, Y: 14}, {X: 9, Y: 17}, {X: 12, Y: 17}}
+	// the house walls, inside the ring
+	genHouse = rect{Min: Vec2i{X: 10, Y: 12}, Max: Vec2i{X: GameWidth - 11, Y: 16}}
+	// power pellets go on the dots closest to these tiles and their mirrors
+	genPellets = []Vec2i{{X: 1, Y: 3}, {X: 1, Y: 23}}
+)
+
+const (
+	GenAttempts     = 100
+	genRemoveChance = 0.6 // of each corridor that may go
+)
+
+type rect struct {
+	Min, Max Vec2i // inclusive
+}
+
+func (r rect) Contains(t Vec2i) bool {
+	return t.X >= r.Min.X && t.X <= r.Max.X && t.Y >= r.Min.Y && t.Y <= r.Max.Y
+}
+
+// genEdge is a corridor from a junction to another junction, to the
+// junction's mirror across the middle or to the left edge for a tunnel.
+type genEdge struct {
+	a, b  Vec2i
+	fixed bool // the ring, the tunnels and the player's corridor always stay
+	open  bool
+}
+
+type generator struct {
+	rng   *rand.Rand
+	nodes map[Vec2i]bool
+	edges []*genEdge
+}
+
+// GenerateBoard makes a new arcade-sized board: symmetric, with the
+// standard ghost house, one or two tunnels, no dead ends and four power
+// pellets near the corners. The same seed always makes the same board.
+func GenerateBoard(seed uint64) (*Board, error) {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	var err error
+	for range GenAttempts {
+		gen := &generator{rng: rng, nodes: map[Vec2i]bool{}}
+		b := gen.board()
+		b.Name = fmt.Sprintf("generated %d", seed)
+		if err = BoardError(b.Name, ValidateBoard(b)); err == nil {
+			return b, nil
+		}
+	}
+	return nil, err
+}
+
+func (gen *generator) board() *Board {
+	tunnels := genTunnels[gen.rng.IntN(len(genTunnels))]
+	gen.lattice(tunnels)
+	gen.removeEdges()
+
+	m := NewMaze(GameWidth, GameHeight)
+	for _, e := range gen.edges {
+		if e.open {
+			fillLine(m, e.a, e.b, Dot)
+		}
+	}
+	for y := range m {
+		for x := range m[y] {
+			t := Vec2i{X: x, Y: y}
+			if genRing.Contains(t) && m[y][x] == Dot {
+				m[y][x] = Empty
+			}
+		}
+	}
+	for _, y := range tunnels {
+		fillLine(m, Vec2i{X: 0, Y: y}, Vec2i{X: genColumns[1] - 1, Y: y}, Empty)
+	}
+	fillLine(m, genHouse.Min.Add(1, 1), genHouse.Max.Add(-1, -1), Empty)
+	m[DefaultPlayerStart.Y][DefaultPlayerStart.X] = Empty
+
+	for _, near := range genPellets {
+		t := nearestDot(m, near)
+		m[t.Y][t.X] = Power
+	}
+	mirror(m)
+	markTunnels(m)
+
+	return &Board{
+		Maze:   m,
+		Player: DefaultPlayerStart,
+		Ghost:  DefaultGhostStart,
+		House:  DefaultHouse,
+		Doors:  slices.Clone(DefaultDoors),
+		art:    -1,
+	}
+}
+
+// lattice adds every junction and corridor between neighbouring junctions.
+func (gen *generator) lattice(tunnels []int) {
+	middle := genColumns[len(genColumns)-1]
+	for _, y := range genRows {
+		for _, x := range genColumns {
+			t := Vec2i{X: x, Y: y}
+			// the tunnel runs where the outer column would cross it
+			if x == genColumns[0] && slices.Contains(tunnels, y) {
+				continue
+			}
+			if genRing.Contains(t) && !slices.Contains(genRingNodes, t) {
+				continue
+			}
+			gen.nodes[t] = true
+		}
+	}
+
+	ring := func(a, b Vec2i) bool {
+		return slices.Contains(genRingNodes, a) && slices.Contains(genRingNodes, b)
+	}
+	for i, y := range genRows {
+		for j, x := range genColumns {
+			a := Vec2i{X: x, Y: y}
+			if !gen.nodes[a] {
+				continue
+			}
+			if j+1 < len(genColumns) {
+				if b := (Vec2i{X: genColumns[j+1], Y: y}); gen.nodes[b] {
+					gen.addEdge(a, b, ring(a, b))
+				}
+			}
+			if i+1 < len(genRows) {
+				if b := (Vec2i{X: x, Y: genRows[i+1]}); gen.nodes[b] {
+					gen.addEdge(a, b, ring(a, b))
+				}
+			}
+			if x == middle {
+				b := Vec2i{X: GameWidth - 1 - x, Y: y}
+				gen.addEdge(a, b, genRing.Contains(a) || y == DefaultPlayerStart.Y)
+			}
+		}
+	}
+	for _, y := range tunnels {
+		gen.addEdge(Vec2i{X: genColumns[1], Y: y}, Vec2i{X: 0, Y: y}, true)
+	}
+}
+
+func (gen *generator) addEdge(a, b Vec2i, fixed bool) {
+	gen.edges = append(gen.edges, &genEdge{a: a, b: b, fixed: fixed, open: true})
+}
+
+// removeEdges closes corridors in random order, keeping at least two ways
+// out of every junction and every junction reachable.
+func (gen *generator) removeEdges() {
+	var edges []*genEdge
+	for _, e := range gen.edges {
+		if !e.fixed {
+			edges = append(edges, e)
+		}
+	}
+	gen.rng.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+
+	for _, e := range edges {
+		if gen.rng.Float64() >= genRemoveChance {
+			continue
+		}
+		if gen.degree(e.a) <= 2 || (gen.nodes[e.b] && gen.degree(e.b) <= 2) {
+			continue
+		}
+		e.open = false
+		if !gen.connected() {
+			e.open = true
+		}
+	}
+}
+
+func (gen *generator) degree(t Vec2i) int {
+	n := 0
+	for _, e := range gen.edges {
+		if e.open && (e.a == t || e.b == t) {
+			n++
+		}
+	}
+	return n
+}
+
+// connected reports whether the left half's junctions are all joined up.
+// The right half is its mirror and the ring joins the two.
+func (gen *generator) connected() bool {
+	var start Vec2i
+	for t := range gen.nodes {
+		start = t
+		break
+	}
+
+	seen := map[Vec2i]bool{start: true}
+	queue := []Vec2i{start}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for _, e := range gen.edges {
+			if !e.open || !gen.nodes[e.a] || !gen.nodes[e.b] {
+				continue
+			}
+			for _, n := range []Vec2i{e.a, e.b} {
+				if (e.a == t || e.b == t) && !seen[n] {
+					seen[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return len(seen) == len(gen.nodes)
+}
+
+// fillLine sets the tiles of a row or column, or of a rectangle, between
+// two tiles.
+func fillLine(m Maze, a, b Vec2i, tile Tile) {
+	for y := min(a.Y, b.Y); y <= max(a.Y, b.Y); y++ {
+		for x := min(a.X, b.X); x <= max(a.X, b.X); x++ {
+			m[y][x] = tile
+		}
+	}
+}
+
+// mirror copies the left half of a maze onto the right half.
+func mirror(m Maze) {
+	w := m.Width()
+	for y := range m {
+		for x := range w / 2 {
+			m[y][w-1-x] = m[y][x]
+		}
+	}
+}
+
+// nearestDot returns the dot on the left half closest to a tile.
+func nearestDot(m Maze, near Vec2i) Vec2i {
+	best, dist := near, float32(-1)
+	for y := range m {
+		for x := range m.Width() / 2 {
+			t := Vec2i{X: x, Y: y}
+			d := near.Distance(t)
+			if m[y][x] == Dot && (dist < 0 || d < dist) {
+				best, dist = t, d
+			}
+		}
+	}
+	return best
+}
+
+// generateMain is the generate subcommand: it prints a generated board as
+// a maze file, or writes it to a file.
+func generateMain(args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	seed := fs.Uint64("seed", rand.Uint64(), "random seed, the same seed makes the same board")
+	out := fs.String("o", "", "write the maze to a file instead of standard output")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s generate [-seed n] [-o file.maze]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	b, err := GenerateBoard(*seed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	text := "# seed " + strconv.FormatUint(*seed, 10) + "\n" + b.String()
+	if *out == "" {
+		fmt.Print(text)
+		return 0
+	}
+	if err := os.WriteFile(*out, []byte(text), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestGenerateBoard(t *testing.T) {
+	for seed := range uint64(50) {
+		b, err := GenerateBoard(seed)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if problems := ValidateBoard(b); len(problems) > 0 {
+			t.Errorf("seed %d: %v", seed, problems)
+		}
+
+		m, w := b.Maze, b.Maze.Width()
+		pellets := 0
+		for y := range m {
+			for x := range w {
+				if m[y][x] != m[y][w-1-x] {
+					t.Errorf("seed %d: %s at %d,%d but %s at %d,%d", seed, m[y][x].Name(), x, y, m[y][w-1-x].Name(), w-1-x, y)
+				}
+				if m[y][x] == Power {
+					pellets++
+				}
+			}
+		}
+		if pellets != 4 {
+			t.Errorf("seed %d: %d power pellets, want 4", seed, pellets)
+		}
+
+		again, err := GenerateBoard(seed)
+		if err != nil {
+			t.Fatalf("seed %d again: %v", seed, err)
+		}
+		if again.String() != b.String() {
+			t.Errorf("seed %d made two different boards", seed)
+		}
+	}
+}
@@ -1,9 +1,7 @@
 package main
 
 import (
-	"fmt"
 	"math"
-	"math/rand/v2"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -35,6 +33,11 @@ const (
 	PinkyId
 	InkyId
 	ClydeId
+	SueId
+	HunterId
+	AmbusherId
+	WandererId
+	ScriptId // any Starlark ghost, whatever built-in it is like
 )
 
 type Behavior interface {
@@ -43,9 +46,15 @@ type Behavior interface {
 	Sprite() Vec2i
 	StartingTile(game *Game) Vec2i
 	StartingDir(game *Game) Direction
-	Chase(game *Game) Vec2i
-	Scatter(game *Game) Vec2i
-	ExitHouse(game *Game) bool
+	Chase(game *Game, self *Ghost) Vec2i
+	Scatter(game *Game, self *Ghost) Vec2i
+	ExitHouse(game *Game, self *Ghost) bool
+}
+
+// Navigator is implemented by behaviors that steer along the shortest path
+// through the MazeGraph instead of the arcade's one tile look ahead.
+type Navigator interface {
+	Navigates() bool
 }
 
 type Ghost struct {
@@ -60,6 +69,7 @@ type Ghost struct {
 	target           Vec2i // temporary for training
 	bounce           int
 	pixelsMovedInDir float32
+	leaving          bool // ExitHouse let it out this tick
 }
 
 // Blinky is the red behavior
@@ -78,6 +88,24 @@ type Inky struct {
 type Clyde struct {
 }
 
+// Sue is Ms. Pac-Man's orange behavior
+type Sue struct {
+}
+
+// Hunter is a pathfinding chaser
+type Hunter struct {
+}
+
+// Ambusher cuts the player off at her next intersection
+type Ambusher struct {
+}
+
+// Wanderer roams to random destinations
+type Wanderer struct {
+	target Vec2i
+	until  float64 // level time to pick a new target
+}
+
 func (g GhostId) String() string {
 	switch g {
 	case BlinkyId:
@@ -88,6 +116,16 @@ func (g GhostId) String() string {
 		return "Inky"
 	case ClydeId:
 		return "Clyde"
+	case SueId:
+		return "Sue"
+	case HunterId:
+		return "Hunter"
+	case AmbusherId:
+		return "Ambusher"
+	case WandererId:
+		return "Wanderer"
+	case ScriptId:
+		return "Script"
 	default:
 		panic("unhandled default case")
 	}
@@ -114,8 +152,9 @@ func (g GhostState) String() string {
 
 func NewGhost(game *Game, b Behavior) *Ghost {
 	spriteY := b.Sprite().Y
-	startX := b.StartingTile(game).X
-	startY := b.StartingTile(game).Y
+	start := game.board().GhostStart(b.StartingTile(game))
+	startX := start.X
+	startY := start.Y
 	dir := b.StartingDir(game)
 
 	g := Ghost{
@@ -136,7 +175,7 @@ func NewGhost(game *Game, b Behavior) *Ghost {
 			vel:       dir.Vector(),
 			nextVel:   dir.Vector(),
 			frame:     0,
-			speedTime: rl.GetTime() + SpeedTime,
+			speedTime: SpeedTime,
 		},
 
 		id:       b.Id(),
@@ -155,17 +194,16 @@ func NewGhost(game *Game, b Behavior) *Ghost {
 		},
 	}
 
-	if g.InHouse() {
+	if g.InHouse(game) {
 		g.state = InHouse
 	} else {
 		g.state = Scatter
 	}
-	fmt.Printf("ghost %s starting at %d,%d in state %s\n", g.id, g.tile.X, g.tile.Y, g.state)
+	aiLog.Debug("ghost start", "ghost", g.id, "tile", g.tile, "state", g.state)
 	return &g
 }
 
 func (g *Ghost) ChooseDirection(game *Game, target Vec2i) Direction {
-	// TODO in Eaten state, set target to (x=14, y=11) (above ghost house door)
 	var validDirections []Direction
 
 	// to match original game, keep this order: Up > Left > Down > Right
@@ -185,7 +223,7 @@ func (g *Ghost) ChooseDirection(game *Game, target Vec2i) Direction {
 	}
 
 	if len(validDirections) == 0 {
-		fmt.Printf("**** no valid directions for %s at %v\n", g.id, target)
+		aiLog.Warn("no valid directions", "ghost", g.id, "tile", g.tile, "target", target)
 		return None
 	}
 
@@ -195,7 +233,7 @@ func (g *Ghost) ChooseDirection(game *Game, target Vec2i) Direction {
 	}
 
 	if g.state == Frightened {
-		return validDirections[rand.IntN(len(validDirections))]
+		return validDirections[game.rng.IntN(len(validDirections))]
 	}
 
 	bestDir := validDirections[0]
@@ -203,13 +241,18 @@ func (g *Ghost) ChooseDirection(game *Game, target Vec2i) Direction {
 	for _, dir := range validDirections {
 		nextTile := dir.GetNextTile(g.tile)
 		dist := target.Distance(nextTile)
+		if g.state == Eaten || (g.state != Frightened && g.navigates()) {
+			// eyes and navigators take the real shortest path
+			if d := game.graph.Distance(nextTile, target); d >= 0 {
+				dist = float32(d)
+			}
+		}
 		if dist < minDist {
 			minDist = dist
 			bestDir = dir
 		}
 	}
 
-	//fmt.Printf("%s cur: %s: best %s at pos: %s, pixels: %0.2f / %0.2f\n", g.id, g.dir, bestDir, g.tile, g.pixelsMoved, g.pixelsMovedInDir)
 	return bestDir
 }
 
@@ -226,32 +269,45 @@ func (g *Ghost) Update(game *Game) {
 		g.pixelsMoved = 0
 	}
 
+	// decided here and kept for the house overlay, since ExitHouse may run
+	// a script and drawing mustn't
+	g.leaving = g.state == InHouse && g.behavior.ExitHouse(game, g)
+
 	if g.state == Scatter {
-		g.target = g.behavior.Scatter(game)
+		g.target = game.board().Corner(g.behavior.Scatter(game, g))
 	} else if g.state == Chase {
-		g.target = g.behavior.Chase(game) // ghost 0 is Blinky
+		g.target = g.behavior.Chase(game, g)
 	} else if g.state == Eaten {
-		g.target = Vec2i{X: 14, Y: 11}
+		g.target = game.board().Ghost
 
-		if g.tile.Equals(14, 11) {
+		if g.tile == g.target {
 			g.state = InHouse
-			g.tile = Vec2i{X: 14, Y: 14}
+			g.tile = game.board().House
 		}
+	} else if g.leaving {
+		// back out above the door
+		g.tile = game.board().Ghost
+		g.pixelsMoved = 0
+		g.state = Scatter
+		g.updateState(game)
+		game.emit(GhostLeftHouse{Ghost: g})
 	}
 
 	curDir := g.dir
 	if game.InTunnel(&g.Entity) {
 		if g.tile.X < 0 && g.dir == Left {
-			g.tile.X = GameWidth - 1
-		} else if g.tile.X >= GameWidth-1 && g.dir == Right {
+			g.tile.X = game.maze.Width() - 1
+		} else if g.tile.X >= game.maze.Width()-1 && g.dir == Right {
 			g.tile.X = 0
 		}
 	} else {
 		g.dir = g.ChooseDirection(game, g.target)
 		if g.dir == None {
-			fmt.Println("no direction")
 			return
 		}
+		if g.dir != curDir {
+			aiLog.Debug("turn", "ghost", g.id, "tile", g.tile, "dir", g.dir, "target", g.target, "state", g.state)
+		}
 	}
 
 	g.vel = g.dir.Vector()
@@ -261,9 +317,9 @@ func (g *Ghost) Update(game *Game) {
 		currentSpeed := g.calculateSpeed(game)
 
 		g.speedPixels += currentSpeed
-		if g.speedTime-game.levelTime <= 0 && g.id == PinkyId {
+		if g.speedTime-game.levelTime <= 0 {
 			g.speedTime = game.levelTime + SpeedTime
-			fmt.Printf("Pinky moving %0.3f/s\n", g.speedPixels)
+			timingLog.Debug("speed", "entity", g.id, "pixels_per_sec", g.speedPixels)
 			g.speedPixels = 0
 		}
 
@@ -304,7 +360,7 @@ func (g *Ghost) updateFright(game *Game) {
 	if g.state != Frightened {
 		return
 	}
-	dt := game.frightTime - rl.GetTime()
+	dt := game.frightTime - game.now()
 	if dt < 0 {
 		g.state = Scatter // temporary, set state will determine state
 		g.updateState(game)
@@ -325,9 +381,9 @@ func (g *Ghost) updateFright(game *Game) {
 }
 
 func (g *Ghost) calculateSpeed(game *Game) float32 {
-	speed := ghostSpeed(game.level)
+	speed := ghostSpeed(game.difficulty())
 	if g.state == Frightened {
-		speed = frightSpeed(game.level)
+		speed = frightSpeed(game.difficulty())
 	}
 
 	// Apply tunnel speed reduction
@@ -341,7 +397,7 @@ func (g *Ghost) calculateSpeed(game *Game) float32 {
 		speed *= 1.5
 	}
 
-	return speed
+	return speed * game.speedScale * game.rules.Preset.GhostSpeed()
 }
 
 func (g *Ghost) updateState(game *Game) {
@@ -362,32 +418,40 @@ func (g *Ghost) updateState(game *Game) {
 	// f. Chase (20s),
 	// g. Scatter (5s),
 	// h. Chase indefinitely.
-	state := Chase
 	// TODO - change based on levels
-	if game.levelTime < 7 { // a
-		state = Scatter
-	} else if game.levelTime < 27 { // b
-		state = Chase
-	} else if game.levelTime < 34 { // c
-		state = Scatter
-	} else if game.levelTime < 54 { // d
-		state = Chase
-	} else if game.levelTime < 61 { // e
-		state = Scatter
-	} else if game.levelTime < 81 { // f
-		state = Chase
-	} else if game.levelTime < 86 { // g
-		state = Scatter
-	}
-
-	//if g.id == PinkyId && g.state != state {
-	//	fmt.Printf("from %s to %s at %0.4f\n", g.state, state, game.levelTime)
-	//}
+	state, _ := modeAt(game.levelTime)
 	g.state = state
 }
 
-func (g *Ghost) InHouse() bool {
-	return g.tile.Y == 14 && (g.tile.X >= 12 && g.tile.X <= 16)
+// modeSchedule is the scatter and chase pattern: each phase lasts until
+// the level time in seconds, after the last one ghosts chase for good.
+var modeSchedule = []struct {
+	until float64
+	state GhostState
+}{
+	{7, Scatter}, {27, Chase}, {34, Scatter}, {54, Chase},
+	{61, Scatter}, {81, Chase}, {86, Scatter},
+}
+
+// modeAt returns the scheduled mode at a level time and the seconds left
+// until it changes, or -1 if it never does.
+func modeAt(levelTime float64) (GhostState, float64) {
+	for _, phase := range modeSchedule {
+		if levelTime < phase.until {
+			return phase.state, phase.until - levelTime
+		}
+	}
+	return Chase, -1
+}
+
+func (g *Ghost) navigates() bool {
+	nav, ok := g.behavior.(Navigator)
+	return ok && nav.Navigates()
+}
+
+func (g *Ghost) InHouse(game *Game) bool {
+	house := game.board().House
+	return g.tile.Y == house.Y && (g.tile.X >= house.X-2 && g.tile.X <= house.X+2)
 }
 
 // GhostSpeed returns ghost speed in pixels per frame based on level
@@ -2,7 +2,10 @@ module github.com/sspencer/mspackerfan
 
 go 1.24.3
 
-require github.com/gen2brain/raylib-go/raylib v0.55.1
+require (
+	github.com/gen2brain/raylib-go/raylib v0.55.1
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
+)
 
 require (
 	github.com/ebitengine/purego v0.7.1 // indirect
@@ -0,0 +1,214 @@
+package main
+
+// MazeGraph is the navigation graph for a board, built once from the Maze
+// whenever a board is mapped. Nodes sit on intersections, dead ends and tunnel
+// mouths; edges are the corridors between them. Distances between every pair
+// of open tiles are computed up front, so distance, path and "which way to
+// go" queries are cheap enough to run for every entity on every frame.
+//
+// Walls never change during a level, so eating dots does not invalidate the
+// graph.
+type MazeGraph struct {
+	maze  Maze
+	tiles []Vec2i       // open tiles, indexed
+	index map[Vec2i]int // open tile -> index into tiles
+	dist  [][]int16     // all pairs shortest distance in tiles, -1 if unreachable
+	nodes []Vec2i
+	edges map[Vec2i][]Edge
+}
+
+// Edge is a corridor leaving a node in direction Dir and arriving at node To
+// after Length tiles. Tunnel edges wrap around the side of the maze.
+type Edge struct {
+	To     Vec2i
+	Dir    Direction
+	Length int
+	Tunnel bool
+}
+
+// neighbor order matches the ghosts' tie-break order: Up > Left > Down > Right
+var graphDirections = []Direction{Up, Left, Down, Right}
+
+func NewMazeGraph(maze Maze) *MazeGraph {
+	mg := &MazeGraph{
+		maze:  maze,
+		index: make(map[Vec2i]int),
+		edges: make(map[Vec2i][]Edge),
+	}
+
+	for y := range maze {
+		for x := range maze[y] {
+			if maze[y][x] != Wall {
+				t := Vec2i{X: x, Y: y}
+				mg.index[t] = len(mg.tiles)
+				mg.tiles = append(mg.tiles, t)
+			}
+		}
+	}
+
+	mg.dist = make([][]int16, len(mg.tiles))
+	for i := range mg.tiles {
+		mg.dist[i] = mg.bfs(mg.tiles[i])
+	}
+
+	for _, t := range mg.tiles {
+		if mg.IsNode(t) {
+			mg.nodes = append(mg.nodes, t)
+		}
+	}
+
+	for _, n := range mg.nodes {
+		for _, dir := range mg.Directions(n) {
+			mg.edges[n] = append(mg.edges[n], mg.walk(n, dir))
+		}
+	}
+
+	return mg
+}
+
+// bfs returns the distance from start to every open tile.
+func (mg *MazeGraph) bfs(start Vec2i) []int16 {
+	dist := make([]int16, len(mg.tiles))
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	dist[mg.index[start]] = 0
+	queue := []Vec2i{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		d := dist[mg.index[cur]]
+		for _, next := range mg.Neighbors(cur) {
+			i := mg.index[next]
+			if dist[i] < 0 {
+				dist[i] = d + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return dist
+}
+
+// walk follows the corridor leaving node in dir until it reaches another node.
+func (mg *MazeGraph) walk(node Vec2i, dir Direction) Edge {
+	e := Edge{Dir: dir}
+	cur := node
+	for {
+		next := dir.GetNextTile(cur)
+		wrapped := mg.maze.Wrap(next)
+		if wrapped != next {
+			e.Tunnel = true
+		}
+		cur = wrapped
+		e.Length++
+
+		if mg.IsNode(cur) {
+			e.To = cur
+			return e
+		}
+
+		// a corridor tile has exactly two exits, keep going the one that is
+		// not back where we came from
+		for _, d := range mg.Directions(cur) {
+			if d != dir.Opposite() {
+				dir = d
+				break
+			}
+		}
+	}
+}
+
+// Neighbors returns the open tiles next to tile, wrapping through tunnels.
+func (mg *MazeGraph) Neighbors(tile Vec2i) []Vec2i {
+	var result []Vec2i
+	for _, dir := range mg.Directions(tile) {
+		result = append(result, mg.maze.Wrap(dir.GetNextTile(tile)))
+	}
+	return result
+}
+
+// Directions returns the directions that can be taken out of tile.
+func (mg *MazeGraph) Directions(tile Vec2i) []Direction {
+	var result []Direction
+	for _, dir := range graphDirections {
+		if mg.maze.IsValidMove(mg.maze.Wrap(dir.GetNextTile(tile))) {
+			result = append(result, dir)
+		}
+	}
+	return result
+}
+
+// IsNode reports whether tile is an intersection, dead end or corner
+// rather than the middle of a straight corridor.
+func (mg *MazeGraph) IsNode(tile Vec2i) bool {
+	dirs := mg.Directions(tile)
+	if len(dirs) != 2 {
+		return true
+	}
+	return dirs[0] != dirs[1].Opposite()
+}
+
+// IsIntersection reports whether tile has three or more exits.
+func (mg *MazeGraph) IsIntersection(tile Vec2i) bool {
+	return len(mg.Directions(tile)) > 2
+}
+
+// Nodes returns every node of the graph.
+func (mg *MazeGraph) Nodes() []Vec2i {
+	return mg.nodes
+}
+
+// Edges returns the corridors leaving node.
+func (mg *MazeGraph) Edges(node Vec2i) []Edge {
+	return mg.edges[node]
+}
+
+// Distance returns the shortest path length in tiles between a and b, or -1
+// if either tile is a wall or b cannot be reached from a.
+func (mg *MazeGraph) Distance(a, b Vec2i) int {
+	i, ok1 := mg.index[mg.maze.Wrap(a)]
+	j, ok2 := mg.index[mg.maze.Wrap(b)]
+	if !ok1 || !ok2 {
+		return -1
+	}
+	return int(mg.dist[i][j])
+}
+
+// NextDirection returns the first step of a shortest path from one tile to
+// another, or None if already there or unreachable. Ties are broken in the
+// order Up, Left, Down, Right like the ghosts do.
+func (mg *MazeGraph) NextDirection(from, to Vec2i) Direction {
+	d := mg.Distance(from, to)
+	if d <= 0 {
+		return None
+	}
+
+	for _, dir := range mg.Directions(from) {
+		if mg.Distance(dir.GetNextTile(from), to) == d-1 {
+			return dir
+		}
+	}
+
+	return None
+}
+
+// Path returns the tiles of a shortest path from one tile to another,
+// excluding from and including to. Returns nil when unreachable.
+func (mg *MazeGraph) Path(from, to Vec2i) []Vec2i {
+	if mg.Distance(from, to) < 0 {
+		return nil
+	}
+
+	var path []Vec2i
+	cur := mg.maze.Wrap(from)
+	for {
+		dir := mg.NextDirection(cur, to)
+		if dir == None {
+			return path
+		}
+		cur = mg.maze.Wrap(dir.GetNextTile(cur))
+		path = append(path, cur)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func testBoard(t *testing.T) *Board {
+	t.Helper()
+	b, err := ParseBoard("test", testMaze)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestGraphDistance(t *testing.T) {
+	graph := NewMazeGraph(testBoard(t).Maze)
+	for _, tt := range []struct {
+		name string
+		a, b Vec2i
+		want int
+	}{
+		{"same tile", Vec2i{X: 5, Y: 5}, Vec2i{X: 5, Y: 5}, 0},
+		{"around the house", Vec2i{X: 5, Y: 5}, Vec2i{X: 5, Y: 1}, 12},
+		{"through the tunnel", Vec2i{X: 1, Y: 5}, Vec2i{X: 9, Y: 5}, 3},
+		{"off the left edge", Vec2i{X: -1, Y: 5}, Vec2i{X: 9, Y: 5}, 1},
+		{"wall", Vec2i{X: 5, Y: 5}, Vec2i{X: 0, Y: 0}, -1},
+		{"behind the door", Vec2i{X: 5, Y: 5}, Vec2i{X: 5, Y: 3}, -1},
+	} {
+		if got := graph.Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: distance %d, want %d", tt.name, got, tt.want)
+		}
+		if got := graph.Distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("%s: distance back %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGraphPath(t *testing.T) {
+	graph := NewMazeGraph(testBoard(t).Maze)
+	for _, tt := range []struct {
+		name     string
+		from, to Vec2i
+		dir      Direction
+		path     []Vec2i
+	}{
+		{"already there", Vec2i{X: 5, Y: 5}, Vec2i{X: 5, Y: 5}, None, nil},
+		{"through the tunnel", Vec2i{X: 1, Y: 5}, Vec2i{X: 9, Y: 5}, Left, []Vec2i{{X: 0, Y: 5}, {X: 10, Y: 5}, {X: 9, Y: 5}}},
+		{"tie goes left before right", Vec2i{X: 5, Y: 7}, Vec2i{X: 5, Y: 5}, Left, nil},
+		{"unreachable", Vec2i{X: 5, Y: 5}, Vec2i{X: 5, Y: 3}, None, nil},
+	} {
+		if got := graph.NextDirection(tt.from, tt.to); got != tt.dir {
+			t.Errorf("%s: direction %s, want %s", tt.name, got, tt.dir)
+		}
+		path := graph.Path(tt.from, tt.to)
+		if tt.path != nil && !slices.Equal(path, tt.path) {
+			t.Errorf("%s: path %v, want %v", tt.name, path, tt.path)
+		}
+		if want := graph.Distance(tt.from, tt.to); want >= 0 && len(path) != want {
+			t.Errorf("%s: path of %d tiles, want %d", tt.name, len(path), want)
+		}
+		if len(path) > 0 && path[len(path)-1] != tt.to {
+			t.Errorf("%s: path ends at %v, want %v", tt.name, path[len(path)-1], tt.to)
+		}
+	}
+}
+
+func TestGraphNodes(t *testing.T) {
+	graph := NewMazeGraph(testBoard(t).Maze)
+	for _, tt := range []struct {
+		tile               Vec2i
+		node, intersection bool
+	}{
+		{Vec2i{X: 1, Y: 1}, true, false},  // corner
+		{Vec2i{X: 3, Y: 1}, false, false}, // corridor
+		{Vec2i{X: 1, Y: 5}, true, true},   // tunnel crossing
+		{Vec2i{X: 5, Y: 5}, false, false}, // the player's start
+	} {
+		if got := graph.IsNode(tt.tile); got != tt.node {
+			t.Errorf("%v: node %t, want %t", tt.tile, got, tt.node)
+		}
+		if got := graph.IsIntersection(tt.tile); got != tt.intersection {
+			t.Errorf("%v: intersection %t, want %t", tt.tile, got, tt.intersection)
+		}
+	}
+
+	// the tunnel edge out of (1,5) wraps to the other side
+	for _, e := range graph.Edges(Vec2i{X: 1, Y: 5}) {
+		if e.Dir == Left && (e.To != Vec2i{X: 9, Y: 5} || e.Length != 3 || !e.Tunnel) {
+			t.Errorf("left edge %+v, want 3 tiles through the tunnel to 9,5", e)
+		}
+	}
+}
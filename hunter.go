@@ -0,0 +1,47 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Hunter (Red)
+//    Chase:
+//       Targets Player's current tile like Blinky, but follows the real
+//       shortest path through the maze instead of the arcade's one tile
+//       look ahead, so it never gets fooled by a wall between them.
+//    Scatter:
+//       Moves to the top-right corner of the maze.
+
+func (b Hunter) Id() GhostId {
+	return HunterId
+}
+
+func (b Hunter) Color() rl.Color {
+	return rl.Maroon
+}
+
+func (b Hunter) StartingTile(game *Game) Vec2i {
+	return Vec2i{X: 13, Y: 11}
+}
+
+func (b Hunter) StartingDir(game *Game) Direction {
+	return Left
+}
+
+func (b Hunter) Sprite() Vec2i {
+	return Vec2i{X: 520, Y: 64}
+}
+
+func (b Hunter) Chase(game *Game, self *Ghost) Vec2i {
+	return game.player.tile
+}
+
+func (b Hunter) Scatter(game *Game, _ *Ghost) Vec2i {
+	return Vec2i{X: 26, Y: 1} // depends on board
+}
+
+func (b Hunter) ExitHouse(_ *Game, _ *Ghost) bool {
+	return true
+}
+
+func (b Hunter) Navigates() bool {
+	return true
+}
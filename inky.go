@@ -41,13 +41,18 @@ func (b Inky) Sprite() Vec2i {
 	return Vec2i{X: 520, Y: 96}
 }
 
-func (b Inky) Chase(game *Game) Vec2i {
+func (b Inky) Chase(game *Game, self *Ghost) Vec2i {
 	p := game.player
-	g := game.ghosts[0] // Blinky
+	g := game.findGhost(BlinkyId)
+	if g == nil {
+		// no Blinky in the pack, pivot around Inky himself
+		g = self
+	}
+
 	var pivot Vec2i
 	switch p.dir {
 	case Up:
-		if ChaseBug {
+		if game.rules.ChaseBug {
 			pivot = p.tile.Add(-2, -2)
 		} else {
 			pivot = p.tile.Add(0, -2)
@@ -68,11 +73,11 @@ func (b Inky) Chase(game *Game) Vec2i {
 	return Vec2i{X: g.tile.X + 2*dx, Y: g.tile.Y + 2*dy}
 }
 
-func (b Inky) Scatter(game *Game) Vec2i {
+func (b Inky) Scatter(game *Game, _ *Ghost) Vec2i {
 	return Vec2i{X: 1, Y: 29} // depends on board
 }
 
-func (b Inky) ExitHouse(game *Game) bool {
+func (b Inky) ExitHouse(game *Game, _ *Ghost) bool {
 	if game.debug {
 		return game.levelTime > 2.0
 	}
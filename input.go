@@ -0,0 +1,402 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type Action int
+
+const (
+	ActionUp Action = iota
+	ActionDown
+	ActionLeft
+	ActionRight
+	ActionPause
+	ActionStart
+	ActionDebug
+	ActionDebugLayout
+	ActionNextBoard
+	ActionChase
+	ActionScatter
+	ActionFrighten
+	ActionRebind
+	ActionConsole
+	ActionSlower
+	ActionFaster
+	ActionStep
+	ActionEditor
+	ActionFullscreen
+	ActionScale
+	ActionRotate
+	ActionCoin
+	ActionSettings
+	ActionMirror   // the editor's mirrored painting
+	ActionPlayTest // the edited board
+	ActionSaveMaze
+	ActionBrush                                      // picks the editor's first brush, the next NumBrushes-1 the rest
+	ActionOverlay = ActionBrush + Action(NumBrushes) // toggles the first debug overlay, the next NumOverlays-1 the rest
+
+	NumActions = int(ActionOverlay) + NumOverlays
+
+	InputBufferFrames = 8   // how long an early press is remembered
+	StickDeadZone     = 0.5 // analog stick travel needed to count as pressed
+	BindingsFile      = "input.json"
+)
+
+func (a Action) String() string {
+	if a >= ActionOverlay && int(a) < NumActions {
+		return "show " + overlays[a-ActionOverlay].Name()
+	}
+	if a >= ActionBrush && a < ActionOverlay {
+		return "brush " + Brush(a-ActionBrush).String()
+	}
+
+	switch a {
+	case ActionUp:
+		return "up"
+	case ActionDown:
+		return "down"
+	case ActionLeft:
+		return "left"
+	case ActionRight:
+		return "right"
+	case ActionPause:
+		return "pause"
+	case ActionStart:
+		return "start"
+	case ActionDebug:
+		return "debug"
+	case ActionDebugLayout:
+		return "debug layout"
+	case ActionNextBoard:
+		return "next board"
+	case ActionChase:
+		return "chase"
+	case ActionScatter:
+		return "scatter"
+	case ActionFrighten:
+		return "frighten"
+	case ActionRebind:
+		return "rebind"
+	case ActionConsole:
+		return "console"
+	case ActionSlower:
+		return "slower"
+	case ActionFaster:
+		return "faster"
+	case ActionStep:
+		return "step"
+	case ActionEditor:
+		return "editor"
+	case ActionFullscreen:
+		return "fullscreen"
+	case ActionScale:
+		return "scale"
+	case ActionRotate:
+		return "rotate"
+	case ActionCoin:
+		return "coin"
+	case ActionSettings:
+		return "settings"
+	case ActionMirror:
+		return "mirror"
+	case ActionPlayTest:
+		return "play test"
+	case ActionSaveMaze:
+		return "save maze"
+	default:
+		panic("unhandled default case")
+	}
+}
+
+// editorOnly reports whether an action only works in the maze editor.
+func (a Action) editorOnly() bool {
+	return a >= ActionMirror && a < ActionOverlay
+}
+
+// playOnly reports whether an action is ignored while the maze editor is
+// open. The editor's keys may also be bound to these.
+func (a Action) playOnly() bool {
+	switch a {
+	case ActionRebind, ActionConsole, ActionSettings, ActionFullscreen, ActionScale, ActionRotate, ActionEditor:
+		return false
+	}
+	return !a.editorOnly()
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	for i := range NumActions {
+		if Action(i).String() == string(text) {
+			*a = Action(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action %q", text)
+}
+
+// Binding is the set of keyboard keys, gamepad buttons and optionally one
+// gamepad stick direction that trigger an action.
+type Binding struct {
+	Keys     []int32 `json:"keys,omitempty"`
+	Buttons  []int32 `json:"buttons,omitempty"`
+	Axis     int32   `json:"axis,omitempty"`
+	AxisSign int32   `json:"axis_sign,omitempty"` // -1 or 1, 0 when no stick
+}
+
+// Bindings maps every action to its binding. It is what gets saved.
+type Bindings map[Action]Binding
+
+func DefaultBindings() Bindings {
+	b := Bindings{
+		ActionUp:          {Keys: []int32{rl.KeyUp}, Buttons: []int32{rl.GamepadButtonLeftFaceUp}, Axis: rl.GamepadAxisLeftY, AxisSign: -1},
+		ActionDown:        {Keys: []int32{rl.KeyDown}, Buttons: []int32{rl.GamepadButtonLeftFaceDown}, Axis: rl.GamepadAxisLeftY, AxisSign: 1},
+		ActionLeft:        {Keys: []int32{rl.KeyLeft}, Buttons: []int32{rl.GamepadButtonLeftFaceLeft}, Axis: rl.GamepadAxisLeftX, AxisSign: -1},
+		ActionRight:       {Keys: []int32{rl.KeyRight}, Buttons: []int32{rl.GamepadButtonLeftFaceRight}, Axis: rl.GamepadAxisLeftX, AxisSign: 1},
+		ActionPause:       {Keys: []int32{rl.KeyP, rl.KeySpace}, Buttons: []int32{rl.GamepadButtonMiddleRight}},
+		ActionStart:       {Keys: []int32{rl.KeyOne}, Buttons: []int32{rl.GamepadButtonRightFaceDown}},
+		ActionDebug:       {Keys: []int32{rl.KeyD}},
+		ActionDebugLayout: {Keys: []int32{rl.KeyL}},
+		ActionNextBoard:   {Keys: []int32{rl.KeyN}},
+		ActionChase:       {Keys: []int32{rl.KeyC}},
+		ActionScatter:     {Keys: []int32{rl.KeyS}},
+		ActionFrighten:    {Keys: []int32{rl.KeyF}},
+		ActionRebind:      {Keys: []int32{rl.KeyF1}, Buttons: []int32{rl.GamepadButtonMiddleLeft}},
+		ActionConsole:     {Keys: []int32{rl.KeyGrave}},
+		ActionSlower:      {Keys: []int32{rl.KeyMinus}},
+		ActionFaster:      {Keys: []int32{rl.KeyEqual}},
+		ActionStep:        {Keys: []int32{rl.KeyPeriod}},
+		ActionEditor:      {Keys: []int32{rl.KeyE}},
+		ActionFullscreen:  {Keys: []int32{rl.KeyF11}},
+		ActionScale:       {Keys: []int32{rl.KeyZero}},
+		ActionRotate:      {Keys: []int32{rl.KeyR}},
+		ActionCoin:        {Keys: []int32{rl.KeyFive}},
+		ActionSettings:    {Keys: []int32{rl.KeyO}},
+		ActionMirror:      {Keys: []int32{rl.KeyM}},
+		ActionPlayTest:    {Keys: []int32{rl.KeyT}},
+		ActionSaveMaze:    {Keys: []int32{rl.KeyS}},
+	}
+	for i := range NumBrushes {
+		b[ActionBrush+Action(i)] = Binding{Keys: []int32{rl.KeyOne + int32(i)}}
+	}
+	for i := range NumOverlays {
+		b[ActionOverlay+Action(i)] = Binding{Keys: []int32{rl.KeyF2 + int32(i)}}
+	}
+	return b
+}
+
+// Input maps keyboard keys and gamepad buttons and sticks to game actions.
+// Update must be called once per frame before any queries.
+type Input struct {
+	bindings  Bindings
+	gamepad   int32
+	file      string // where bindings are saved, empty to not save
+	stick     []bool // stick state by action last frame, to detect presses
+	stickNow  []bool
+	buffered  Action // last direction pressed
+	bufferAge int    // frames since it was pressed
+}
+
+func NewInput(file string) *Input {
+	in := &Input{
+		bindings:  DefaultBindings(),
+		file:      file,
+		stick:     make([]bool, NumActions),
+		stickNow:  make([]bool, NumActions),
+		bufferAge: InputBufferFrames + 1,
+	}
+	if file == "" {
+		return in
+	}
+
+	if err := in.load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "input bindings %s: %v, using defaults\n", file, err)
+	}
+	return in
+}
+
+// BindingsPath returns the default location of the saved key bindings.
+func BindingsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return BindingsFile
+	}
+	return filepath.Join(dir, "mspackerfan", BindingsFile)
+}
+
+func (in *Input) load() error {
+	data, err := os.ReadFile(in.file)
+	if err != nil {
+		return err
+	}
+
+	// by name, so actions a newer or older version saved don't spoil the file
+	saved := map[string]Binding{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	// actions missing from an older file keep their defaults
+	for name, b := range saved {
+		var a Action
+		if err := a.UnmarshalText([]byte(name)); err != nil {
+			gameLog.Warn("skipping input binding", "file", in.file, "err", err)
+			continue
+		}
+		in.bindings[a] = b
+	}
+	return nil
+}
+
+// Save writes the current bindings to the bindings file.
+func (in *Input) Save() error {
+	if in.file == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(in.bindings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(in.file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(in.file, data, 0o644)
+}
+
+func (in *Input) Update() {
+	in.stick, in.stickNow = in.stickNow, in.stick
+	for a := range NumActions {
+		in.stickNow[a] = in.stickDown(in.bindings[Action(a)])
+	}
+
+	in.bufferAge++
+	for _, a := range []Action{ActionUp, ActionDown, ActionLeft, ActionRight} {
+		if in.Pressed(a) {
+			in.buffered = a
+			in.bufferAge = 0
+		}
+	}
+}
+
+func (in *Input) stickDown(b Binding) bool {
+	if b.AxisSign == 0 || !rl.IsGamepadAvailable(in.gamepad) {
+		return false
+	}
+	return rl.GetGamepadAxisMovement(in.gamepad, b.Axis)*float32(b.AxisSign) > StickDeadZone
+}
+
+// Pressed reports whether the action was triggered this frame.
+func (in *Input) Pressed(a Action) bool {
+	b := in.bindings[a]
+	for _, key := range b.Keys {
+		if rl.IsKeyPressed(key) {
+			return true
+		}
+	}
+
+	if rl.IsGamepadAvailable(in.gamepad) {
+		for _, button := range b.Buttons {
+			if rl.IsGamepadButtonPressed(in.gamepad, button) {
+				return true
+			}
+		}
+	}
+
+	return in.stickNow[a] && !in.stick[a]
+}
+
+// Down reports whether the action is being held.
+func (in *Input) Down(a Action) bool {
+	b := in.bindings[a]
+	for _, key := range b.Keys {
+		if rl.IsKeyDown(key) {
+			return true
+		}
+	}
+
+	if rl.IsGamepadAvailable(in.gamepad) {
+		for _, button := range b.Buttons {
+			if rl.IsGamepadButtonDown(in.gamepad, button) {
+				return true
+			}
+		}
+	}
+
+	return in.stickNow[a]
+}
+
+// Direction returns the direction being held or, failing that, one pressed
+// within the last InputBufferFrames frames, so a tap slightly before a turn
+// or a held stick is not lost. Returns None when there is neither.
+func (in *Input) Direction() Direction {
+	// the most recent press wins when several directions are held
+	if in.bufferAge <= InputBufferFrames || in.Down(in.buffered) {
+		return actionDirection(in.buffered)
+	}
+
+	for _, a := range []Action{ActionUp, ActionDown, ActionLeft, ActionRight} {
+		if in.Down(a) {
+			return actionDirection(a)
+		}
+	}
+
+	return None
+}
+
+func actionDirection(a Action) Direction {
+	switch a {
+	case ActionUp:
+		return Up
+	case ActionDown:
+		return Down
+	case ActionLeft:
+		return Left
+	case ActionRight:
+		return Right
+	default:
+		return None
+	}
+}
+
+func (in *Input) Binding(a Action) Binding {
+	return in.bindings[a]
+}
+
+func (in *Input) SetBinding(a Action, b Binding) {
+	in.bindings[a] = b
+}
+
+// Conflict finds another action with one of the keys or buttons of a
+// binding for a, returning it and the name of the key or button. The
+// editor's actions don't conflict with the ones it ignores.
+func (in *Input) Conflict(a Action, b Binding) (Action, string, bool) {
+	for i := range NumActions {
+		other := Action(i)
+		if other == a || a.editorOnly() && other.playOnly() || a.playOnly() && other.editorOnly() {
+			continue
+		}
+		for _, key := range b.Keys {
+			if slices.Contains(in.bindings[other].Keys, key) {
+				return other, keyName(key), true
+			}
+		}
+		for _, button := range b.Buttons {
+			if slices.Contains(in.bindings[other].Buttons, button) {
+				return other, buttonName(button), true
+			}
+		}
+	}
+	return 0, "", false
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestDefaultBindingsDontConflict(t *testing.T) {
+	in := &Input{bindings: DefaultBindings()}
+	for a, b := range in.bindings {
+		if other, name, ok := in.Conflict(a, b); ok {
+			t.Errorf("%s: %s is also %s", a, name, other)
+		}
+	}
+}
+
+// The editor's keys may be shared with the game's, but not with the
+// actions that still work while it is open.
+func TestEditorBindingConflicts(t *testing.T) {
+	in := &Input{bindings: DefaultBindings()}
+	for _, tt := range []struct {
+		a    Action
+		key  int32
+		want bool
+	}{
+		{ActionMirror, rl.KeyC, false},         // chase
+		{ActionScatter, rl.KeyM, false},        // mirror
+		{ActionMirror, rl.KeyO, true},          // settings
+		{ActionSettings, rl.KeyT, true},        // play test
+		{ActionBrush, rl.KeyTwo, true},         // the second brush
+		{ActionPlayTest, rl.KeyE, true},        // editor
+		{ActionCoin, rl.KeyM, false},           // mirror
+		{ActionConsole, rl.KeyOne, true},       // the first brush
+		{ActionStart, rl.KeySix, false},        // a brush
+		{ActionSaveMaze, rl.KeyF11, true},      // fullscreen
+		{ActionOverlay, rl.KeyThree, false},    // a brush
+		{ActionDebugLayout, rl.KeyNine, false}, // a brush
+	} {
+		_, _, got := in.Conflict(tt.a, Binding{Keys: []int32{tt.key}})
+		if got != tt.want {
+			t.Errorf("%s on %s: conflict %t, want %t", tt.a, keyName(tt.key), got, tt.want)
+		}
+	}
+}
+
+// Bindings of actions this version doesn't have, like an overlay that was
+// removed, are skipped and the rest of the file still loads.
+func TestLoadSkipsUnknownActions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), BindingsFile)
+	data := `{"show nothing": {"keys": [65]}, "pause": {"keys": [66]}}`
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	in := NewInput(file)
+	if got := in.Binding(ActionPause).Keys; !slices.Equal(got, []int32{66}) {
+		t.Errorf("pause keys %v, want [66]", got)
+	}
+	if got := in.Binding(ActionCoin).Keys; !slices.Equal(got, []int32{rl.KeyFive}) {
+		t.Errorf("coin keys %v, want the default", got)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	LocaleDir       = "locales"
+	DefaultLanguage = "en"
+)
+
+// Catalog translates the text the game shows. Messages are looked up by
+// their English text, so a catalog is a JSON object from English to the
+// language, and anything it leaves out stays English. Names that are also
+// identifiers, like ghost and action names in console commands and saved
+// bindings, aren't translated at the source: only where they are shown.
+type Catalog struct {
+	Language string
+	messages map[string]string
+}
+
+// LoadCatalog reads a language's catalog from LocaleDir. English needs no
+// file.
+func LoadCatalog(lang string) (*Catalog, error) {
+	c := &Catalog{Language: lang, messages: map[string]string{}}
+	if lang == DefaultLanguage {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(LocaleDir, lang+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("unknown language %q, want one of %s", lang, strings.Join(Languages(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.messages); err != nil {
+		return nil, fmt.Errorf("%s catalog: %w", lang, err)
+	}
+	return c, nil
+}
+
+// Languages returns English and the languages with a catalog.
+func Languages() []string {
+	langs := []string{DefaultLanguage}
+	files, _ := filepath.Glob(filepath.Join(LocaleDir, "*.json"))
+	for _, f := range files {
+		langs = append(langs, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	slices.Sort(langs[1:])
+	return langs
+}
+
+// SystemLanguage picks the language of the user's locale, as set by
+// LC_ALL, LC_MESSAGES or LANG, when there is a catalog for it.
+func SystemLanguage() string {
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(v)
+		if locale == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(locale, "_")
+		lang, _, _ = strings.Cut(lang, ".")
+		if slices.Contains(Languages(), lang) {
+			return lang
+		}
+		return DefaultLanguage
+	}
+	return DefaultLanguage
+}
+
+// T translates a message.
+func (c *Catalog) T(msg string) string {
+	if t, ok := c.messages[msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// tr translates a message into the game's language.
+func (g *Game) tr(msg string) string {
+	return g.catalog.T(msg)
+}
+
+// setLanguage switches the game's text to a language.
+func (g *Game) setLanguage(lang string) error {
+	c, err := LoadCatalog(lang)
+	if err != nil {
+		return err
+	}
+	g.catalog = c
+	return nil
+}
@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestCatalogsNameEveryGhost(t *testing.T) {
+	for _, lang := range Languages()[1:] {
+		c, err := LoadCatalog(lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id := BlinkyId; id <= ScriptId; id++ {
+			if _, ok := c.messages[id.String()]; !ok {
+				t.Errorf("%s: no translation of %s", lang, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Log categories, each enabled with the -log flag. Disabled categories only
+// pass on warnings and errors, so logging in the update loop costs next to
+// nothing unless it was asked for.
+var (
+	aiLog        = quietLogger("ai")        // target selection, modes, the house, ghost scripts
+	movementLog  = quietLogger("movement")  // tiles, turns, tunnels
+	collisionLog = quietLogger("collision") // dots, ghosts, fruit
+	timingLog    = quietLogger("timing")    // speeds and level timers
+	gameLog      = quietLogger("game")      // files the game loads and saves
+)
+
+var logCategories = map[string]**slog.Logger{
+	"ai":        &aiLog,
+	"movement":  &movementLog,
+	"collision": &collisionLog,
+	"timing":    &timingLog,
+	"game":      &gameLog,
+}
+
+// quietLogger is a disabled category, writing only warnings and errors to
+// stderr.
+func quietLogger(name string) *slog.Logger {
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})
+	return slog.New(handler).With("cat", name)
+}
+
+// LogCategories returns the names accepted by SetupLogging, sorted.
+func LogCategories() []string {
+	names := make([]string, 0, len(logCategories))
+	for name := range logCategories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetupLogging enables a comma separated list of log categories, or "all",
+// writing them as text to stderr.
+func SetupLogging(list string) error {
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+			continue
+		case "all":
+			for name, logger := range logCategories {
+				*logger = slog.New(handler).With("cat", name)
+			}
+			continue
+		}
+
+		logger, ok := logCategories[name]
+		if !ok {
+			return fmt.Errorf("unknown log category %q, want one of: all, %s", name, strings.Join(LogCategories(), ", "))
+		}
+		*logger = slog.New(handler).With("cat", name)
+	}
+	return nil
+}
+
+// logEvent logs an event in the category it belongs to.
+func logEvent(_ *Game, tick int, e Event) {
+	logger := aiLog
+	switch e.(type) {
+	case PlayerTurned:
+		logger = movementLog
+	case DotEaten, PelletEaten, GhostEaten, PlayerDied, FruitEaten:
+		logger = collisionLog
+	case GameStarted, LevelCleared, ExtraLife, FruitSpawned, GameOver:
+		logger = timingLog
+	}
+	logger.Info(e.String(), "tick", tick, "event", EventName(e))
+}
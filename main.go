@@ -3,70 +3,307 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math/rand/v2"
+	"os"
 	"runtime/debug"
+	"strings"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 const (
-	ScreenHeight   = 36
-	TopPadding     = 3 // make room for score
-	GameWidth      = 28
-	GameHeight     = 31
-	Zoom           = 4
-	Size           = 8
-	Pixel          = Size * Zoom
-	ChaseBug       = true // error in chase state in original game
-	FrightDuration = 6.0
+	TopPadding      = 3  // make room for score
+	BottomPadding   = 2  // and for lives and fruit
+	GameWidth       = 28 // size of the built-in boards
+	GameHeight      = 31
+	MinScreenWidth  = GameWidth                               // tiles, smaller mazes are centered
+	MinScreenHeight = TopPadding + GameHeight + BottomPadding // tiles
+	Zoom            = 4
+	Size            = 8
+	Pixel           = Size * Zoom
+	ChaseBug        = true // error in chase state in original game, see Rules
+	FrightDuration  = 6.0  // seconds on normal difficulty
+
+	TickTime         = 1.0 / 60 // seconds simulated per tick
+	MaxFrameTime     = 0.25     // longest frame caught up on, e.g. after a stall
+	MaxTicksPerFrame = 10
+	MinTimeScale     = 0.25
+	MaxTimeScale     = 4
 )
 
 type Game struct {
-	font     rl.Texture2D
+	font     *Font
 	texture  rl.Texture2D
 	image    *rl.Image
 	player   *Player
 	ghosts   []*Ghost
-	shader   rl.Shader
+	input    *Input
+	rebind   *RebindScreen
+	boards   []*Board
 	boardNum int
 	level    int
 	maze     Maze
+	graph    *MazeGraph
 	//maze        [31][28]Tile
-	tunnels     []Vec2i
-	camera2     rl.Camera2D
-	paused      bool
-	debug       bool
-	moved       bool
-	debugLayout bool
-	highScore   int
-	startTime   float64
-	levelTime   float64
-	frightTime  float64
-	dotsEaten   int
+	tunnels       []Vec2i
+	bus           EventBus
+	audio         *Audio
+	popups        *Popups
+	stats         *Stats
+	fruit         *Fruit
+	tracer        *Tracer
+	console       *Console
+	scripts       []string // files RunScript is running, innermost last
+	editor        *Editor
+	rng           *rand.Rand  // all randomness in the simulation, see Seed
+	seed          uint64      // rng's last seed, recorded with replays
+	god           bool        // ghosts can't catch the player
+	speedScale    float32     // multiplies every actor's speed
+	camera2       rl.Camera2D // maze to screen units, see Screen
+	screen        *Screen
+	paused        bool
+	debug         bool
+	moved         bool
+	overlays      *Overlays
+	mode          GameMode
+	pool          BoardPool
+	generated     int // endless mode's generated board in boards, -1 before the first
+	scores        *HighScores
+	phase         Phase
+	phaseEnd      float64 // when the phase is over, see setPhase
+	credits       int
+	catalog       *Catalog // the language of the game's text
+	rules         Rules
+	config        *Config // the settings file, which flags may override
+	settings      *SettingsMenu
+	highScore     int
+	simTime       float64 // seconds of simulated time, see now
+	timeScale     float64 // simulated seconds per real second
+	lag           float64 // simulated time owed to the next frame
+	startTime     float64
+	levelTime     float64
+	frightTime    float64
+	tick          int // simulation steps since the game started
+	dotsEaten     int
+	dotsLeft      int
+	ghostsEaten   int // since the last power pellet
+	fruitsSpawned int // this level
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(validateMain(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		os.Exit(generateMain(os.Args[2:]))
+	}
+
+	// flags start out with the settings file's values
+	config := LoadConfig(ConfigPath())
+
 	debugMode := false
+	demoMode := false
+	mute := false
+	wavDir := ""
+	ghostList := DefaultGhosts
+	recordFile := ""
+	replayFile := ""
+	showStats := false
+	logList := ""
+	traceFile := ""
+	overlayList := DefaultOverlays
+	var seed uint64
+	script := ""
+	scale := config.Scale
+	rotate := config.Rotate
+	fullscreen := config.Fullscreen
+	effectList := config.Effects
+	effectParams := ""
+	modeName := ModeArcade.String()
+	poolList := DefaultPool
+	lang := config.Language
+	difficulty := config.Difficulty
+	lives := config.Lives
+	bonusLife := config.BonusLife
+	chaseBug := config.ChaseBug
+	preTurn := float64(config.Cornering.PreTurn)
+	postTurn := float64(config.Cornering.PostTurn)
+	volume := float64(config.Volume)
 	flag.BoolVar(&debugMode, "d", false, "enable debug mode")
+	flag.BoolVar(&demoMode, "demo", false, "attract mode, the bot plays the game")
+	flag.BoolVar(&mute, "mute", false, "no sound")
+	flag.StringVar(&wavDir, "wav", "", "render the sound effects to WAV files in `dir` and exit")
+	flag.StringVar(&ghostList, "ghosts", DefaultGhosts, "comma separated ghost behaviors: "+strings.Join(BehaviorNames(), ", "))
+	flag.StringVar(&recordFile, "record", "", "record the player's moves to `file`")
+	flag.StringVar(&replayFile, "replay", "", "play back the moves recorded in `file`")
+	flag.BoolVar(&showStats, "stats", false, "print event counts on exit")
+	flag.StringVar(&logList, "log", "", "comma separated log categories to enable: all, "+strings.Join(LogCategories(), ", "))
+	flag.StringVar(&traceFile, "trace", "", "write a JSON line per tick with every actor's state to `file`")
+	flag.StringVar(&overlayList, "overlays", DefaultOverlays, "comma separated debug overlays to show in debug mode: "+strings.Join(OverlayNames(), ", "))
+	flag.Uint64Var(&seed, "seed", 0, "seed for the ghosts' and fruit's random choices, 0 for a random seed")
+	flag.StringVar(&script, "exec", "", "run the console commands in `file` at startup")
+	flag.IntVar(&scale, "scale", scale, "window size in multiples of the arcade's 224x288 screen, 0 for the largest that fits")
+	flag.BoolVar(&rotate, "rotate", rotate, "turn the picture a quarter like a cabinet with its monitor on its side")
+	flag.BoolVar(&fullscreen, "fullscreen", fullscreen, "start in fullscreen")
+	flag.StringVar(&effectList, "effects", effectList, "comma separated post-processing effects in order, or none: "+strings.Join(EffectNames(), ", "))
+	flag.StringVar(&effectParams, "effect-params", "", "comma separated effect.param=value, e.g. scanlines.strength=0.6")
+	flag.StringVar(&modeName, "mode", modeName, "game mode: arcade, or endless for a board from the pool every level")
+	flag.StringVar(&poolList, "pool", DefaultPool, "comma separated board sources for endless mode")
+	flag.StringVar(&lang, "lang", lang, "language of the game's text, by default the system's: "+strings.Join(Languages(), ", "))
+	flag.StringVar(&difficulty, "difficulty", difficulty, "easy, normal or hard: how long ghosts stay frightened and how fast they move")
+	flag.IntVar(&lives, "lives", lives, fmt.Sprintf("lives at the start of a game, 1-%d", MaxLives))
+	flag.IntVar(&bonusLife, "bonus-life", bonusLife, "score that earns an extra life, 0 for none")
+	flag.BoolVar(&chaseBug, "chase-bug", chaseBug, "Pinky and Inky aim up and left when Ms. Packer faces up, as on the arcade")
+	flag.Float64Var(&preTurn, "pre-turn", preTurn, fmt.Sprintf("pixels before a tile's center Ms. Packer may start a turn, 0-%d", MaxCornering))
+	flag.Float64Var(&postTurn, "post-turn", postTurn, fmt.Sprintf("pixels past a tile's center Ms. Packer may still turn, 0-%d", MaxCornering))
+	flag.Float64Var(&volume, "volume", volume, "sound volume from 0 to 1")
 	flag.Parse()
 
+	if err := SetupLogging(logList); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if wavDir != "" {
+		if err := ExportSounds(wavDir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	ghosts, err := ParseBehaviors(ghostList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	mode, err := ParseGameMode(modeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	pool, err := ParseBoardPool(poolList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	preset, err := ParsePreset(difficulty)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	// less GC
 	debug.SetGCPercent(200)
 
 	rl.SetTraceLogLevel(rl.LogWarning)
 
-	rl.InitWindow(GameWidth*Pixel, ScreenHeight*Pixel, "Ms. Player Fan")
+	rl.SetConfigFlags(rl.FlagWindowResizable)
+	rl.InitWindow(MinScreenWidth*Size, MinScreenHeight*Size, "Ms. Player Fan")
 	defer rl.CloseWindow()
 
 	rl.SetTargetFPS(60)
 
-	font := rl.LoadTexture("font.png")
-	defer rl.UnloadTexture(font)
-	texture := rl.LoadTexture("frozen_tundra.png")
+	font, err := LoadFont(FontFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "font: %v, using the built-in font\n", err)
+		font = DefaultFont()
+	}
+	defer font.Close()
+	texture, image := LoadSpriteSheet("frozen_tundra.png")
 	defer rl.UnloadTexture(texture)
-	image := rl.LoadImageFromTexture(texture)
 	defer rl.UnloadImage(image)
 
-	g := initGame(font, texture, image, debugMode)
+	input := NewInput(BindingsPath())
+	var controller Controller = Human{input: input}
+	if demoMode {
+		controller = NewBot()
+	}
+	if replayFile != "" {
+		replay, err := LoadReplay(replayFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		controller = replay
+		if seed == 0 {
+			seed = replay.seed
+		}
+	}
+
+	g := initGame(font, texture, image, input, controller, ghosts, debugMode)
+	if err := g.overlays.Enable(overlayList); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	g.config = config
+	g.rules = Rules{Preset: preset, Lives: min(max(lives, 1), MaxLives), BonusLife: max(bonusLife, 0), ChaseBug: chaseBug,
+		Cornering: Cornering{PreTurn: float32(preTurn), PostTurn: float32(postTurn)}.Clamp()}
+	g.player.lives = g.rules.Lives
+	g.player.cornering = g.rules.Cornering
+	if lang == "" {
+		lang = SystemLanguage()
+	}
+	if err := g.setLanguage(lang); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if seed != 0 {
+		g.Seed(seed)
+	}
+	g.setMode(mode, pool)
+	defer g.screen.Close()
+	if err := g.screen.post.Set(effectList); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := g.screen.post.SetParams(effectParams); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	g.screen.SetRotated(rotate)
+	g.screen.SetScale(scale)
+	if fullscreen {
+		g.screen.ToggleFullscreen()
+	}
+
+	var backend AudioBackend = NewNullAudio()
+	if !mute {
+		backend = NewRaylibAudio()
+	}
+	g.audio = NewAudio(backend)
+	defer g.audio.Close()
+	g.audio.SetVolume(min(max(float32(volume), 0), 1))
+	g.bus.Subscribe(g.audio)
+
+	if recordFile != "" {
+		recorder, err := NewRecorder(recordFile, g.seed)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer recorder.Close()
+		g.bus.Subscribe(recorder)
+	}
+
+	if traceFile != "" {
+		tracer, err := NewTracer(traceFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer tracer.Close()
+		g.tracer = tracer
+		g.bus.Subscribe(tracer)
+	}
+
+	// after the subscribers, so a script's ticks are heard, traced and recorded
+	if script != "" {
+		if err := g.RunScript(script); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
 
 	for !rl.WindowShouldClose() {
 		g.Update()
@@ -77,23 +314,34 @@ func main() {
 
 		rl.EndDrawing()
 	}
+
+	if g.phase != PhaseGameOver {
+		g.recordScore() // game over recorded it already
+	}
+	if showStats {
+		fmt.Print(g.stats)
+	}
+	if demoMode {
+		fmt.Printf("demo score: %d, dots: %d, level: %d\n", g.player.score, g.dotsEaten, g.level)
+	}
 }
 
 // newGame := func() *Game {}
-func initGame(font, texture rl.Texture2D, image *rl.Image, debugMode bool) *Game {
+func initGame(font *Font, texture rl.Texture2D, image *rl.Image, input *Input, controller Controller, ghosts []Behavior, debugMode bool) *Game {
 	g := &Game{}
 	g.font = font
 	g.texture = texture
 	g.image = image
-	g.shader = chromaShader()
-	g.maze = make(Maze, GameHeight)
-	g.highScore = 0
-	g.startTime = rl.GetTime()
+	g.scores = LoadHighScores(ScoresPath())
+	g.catalog = &Catalog{Language: DefaultLanguage}
+	g.rules = Rules{Preset: PresetNormal, Lives: StartingLives, BonusLife: ExtraLifeScore, ChaseBug: ChaseBug, Cornering: DefaultCornering}
+	g.config = DefaultConfig()
+	g.startTime = g.now()
+	g.timeScale = 1
 	g.level = 1
+	g.generated = -1
 	g.debug = debugMode
-	for i := 0; i < GameHeight; i++ {
-		g.maze[i] = make([]Tile, GameWidth)
-	}
+	g.input = input
 
 	g.camera2 = rl.Camera2D{
 		Offset:   rl.Vector2{Y: TopPadding * Pixel},
@@ -102,9 +350,22 @@ func initGame(font, texture rl.Texture2D, image *rl.Image, debugMode bool) *Game
 		Zoom:     1,
 	}
 
+	g.screen = NewScreen()
+	g.popups = &Popups{}
+	g.overlays = NewOverlays()
+	g.console = NewConsole()
+	g.Seed(rand.Uint64())
+	g.speedScale = 1
+	g.stats = NewStats()
+	g.bus.Subscribe(SubscriberFunc(logEvent))
+	g.bus.Subscribe(g.stats)
+	g.bus.Subscribe(g.popups)
+
+	g.loadBoards()
 	g.mapBoard()
+	g.emit(GameStarted{})
 	for i, t := range g.tunnels {
-		fmt.Printf("Tunnel %d: %v\n", i, t)
+		movementLog.Debug("tunnel", "index", i, "tile", t)
 	}
 
 	//dots := 0
@@ -119,17 +380,16 @@ func initGame(font, texture rl.Texture2D, image *rl.Image, debugMode bool) *Game
 
 	// g.maze.String()
 
-	g.player = NewPlayer()
+	g.player = NewPlayer(controller, g.board().Player)
 	//if g.debug {
 	//	g.ghosts = make([]*Ghost, 1)
 	//	g.ghosts[0] = NewGhost(g, Blinky{})
 	//
 	//} else {
-	g.ghosts = make([]*Ghost, 4)
-	g.ghosts[0] = NewGhost(g, Blinky{})
-	g.ghosts[1] = NewGhost(g, Pinky{})
-	g.ghosts[2] = NewGhost(g, Inky{})
-	g.ghosts[3] = NewGhost(g, Clyde{})
+	g.ghosts = make([]*Ghost, len(ghosts))
+	for i, b := range ghosts {
+		g.ghosts[i] = NewGhost(g, b)
+	}
 	//}
 	return g
 }
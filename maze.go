@@ -1,6 +1,7 @@
 package main
 
 import (
+	"slices"
 	"strings"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -98,13 +99,61 @@ func (m Maze) Pretty() string {
 	return sb.String()
 }
 
+func (m Maze) Width() int {
+	return len(m[0])
+}
+
+func (m Maze) Height() int {
+	return len(m)
+}
+
+// Contains reports whether a tile is inside the maze.
+func (m Maze) Contains(v Vec2i) bool {
+	return v.X >= 0 && v.X < m.Width() && v.Y >= 0 && v.Y < m.Height()
+}
+
+// Clamp moves a tile outside the maze to the nearest tile on its edge.
+func (m Maze) Clamp(v Vec2i) Vec2i {
+	v.X = min(max(v.X, 0), m.Width()-1)
+	v.Y = min(max(v.Y, 0), m.Height()-1)
+	return v
+}
+
+func NewMaze(width, height int) Maze {
+	m := make(Maze, height)
+	for y := range m {
+		m[y] = make([]Tile, width)
+	}
+	return m
+}
+
+func (m Maze) Clone() Maze {
+	c := make(Maze, len(m))
+	for y := range m {
+		c[y] = slices.Clone(m[y])
+	}
+	return c
+}
+
 func (m Maze) IsValidMove(tile Vec2i) bool {
-	if tile.X < 0 || tile.X >= len(m[0]) || tile.Y < 0 || tile.Y >= len(m) {
+	if !m.Contains(tile) {
 		return false
 	}
 	return m[tile.Y][tile.X] != Wall
 }
 
+// Wrap returns tile with its column wrapped around the left and right edges
+// of the maze, which is how the tunnels connect both sides.
+func (m Maze) Wrap(tile Vec2i) Vec2i {
+	w := m.Width()
+	if tile.X < 0 {
+		tile.X += w
+	} else if tile.X >= w {
+		tile.X -= w
+	}
+	return tile
+}
+
 // always get starting "dot" color at boardNum pos 1,1
 // then go row by row to get either dot (.), power (o), border (pixelX) or empty (<sp>)
 
@@ -133,50 +182,52 @@ func (g *Game) readPixelArea(startX, startY, width, height int32) (uint64, strin
 	return result, tile.String()
 }
 
+// mapBoard sets up the current board for play with every dot in place.
 func (g *Game) mapBoard() {
+	g.maze = g.board().Maze.Clone()
 
-	offset := g.boardNum * GameHeight * Size
-	var piece Tile
-	for y := 0; y < GameHeight; y++ {
-		for x := 0; x < GameWidth; x++ {
-			p, _ := g.readPixelArea(int32(x*Size), int32(y*Size+offset), Size, Size)
-			if p == 0 {
-				piece = Empty
-			} else if p == DotMask { // dot
-				piece = Dot
-			} else if p == PowerMask {
-				piece = Power
-			} else {
-				piece = Wall
+	g.dotsLeft = 0
+	for _, row := range g.maze {
+		for _, tile := range row {
+			if tile == Dot || tile == Power {
+				g.dotsLeft++
 			}
-
-			g.maze[y][x] = piece
 		}
 	}
 
-	// find tunnels
-	g.tunnels = make([]Vec2i, 0, 4)
-	for y := 0; y < GameHeight; y++ {
-		if g.maze[y][0] == Empty &&
-			(g.maze[y][1] == Empty || g.maze[y][1] == Dot) &&
-			(g.maze[y][2] == Empty || g.maze[y][2] == Dot) {
-			g.maze[y][0] = Tunnel
-			g.tunnels = append(g.tunnels, Vec2i{X: 0, Y: y})
+	g.tunnels = markTunnels(g.maze)
+	g.graph = NewMazeGraph(g.maze)
+	g.layout()
+}
+
+// markTunnels marks the open ends of rows on the left and right edges as
+// tunnels and returns them.
+func markTunnels(m Maze) []Vec2i {
+	open := func(t Tile) bool { return t == Empty || t == Tunnel }
+	path := func(t Tile) bool { return t == Empty || t == Dot || t == Tunnel }
+
+	w := m.Width()
+	tunnels := make([]Vec2i, 0, 4)
+	for y := range m {
+		if open(m[y][0]) &&
+			path(m[y][1]) && path(m[y][2]) {
+			m[y][0] = Tunnel
+			tunnels = append(tunnels, Vec2i{X: 0, Y: y})
 		}
 
-		if (g.maze[y][GameWidth-3] == Empty || g.maze[y][GameWidth-3] == Dot) &&
-			(g.maze[y][GameWidth-2] == Empty || g.maze[y][GameWidth-2] == Dot) &&
-			g.maze[y][GameWidth-1] == Empty {
-			g.maze[y][GameWidth-1] = Tunnel
-			g.tunnels = append(g.tunnels, Vec2i{X: 0, Y: y})
+		if path(m[y][w-3]) && path(m[y][w-2]) &&
+			open(m[y][w-1]) {
+			m[y][w-1] = Tunnel
+			tunnels = append(tunnels, Vec2i{X: w - 1, Y: y})
 		}
 	}
+	return tunnels
 }
 
 func (g *Game) InTunnel(e *Entity) bool {
 	x, y := e.tile.X, e.tile.Y
 	for _, t := range g.tunnels {
-		if y == t.Y && (x <= 0 || x >= GameWidth-1) {
+		if y == t.Y && (x <= 0 || x >= g.maze.Width()-1) {
 			return true
 		}
 	}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GameMode is how the levels follow each other.
+type GameMode int
+
+const (
+	ModeArcade  GameMode = iota // the arcade's board order
+	ModeEndless                 // a board from the pool every level
+
+	DefaultPool = "builtin,custom,generated"
+)
+
+func (m GameMode) String() string {
+	switch m {
+	case ModeArcade:
+		return "arcade"
+	case ModeEndless:
+		return "endless"
+	default:
+		panic("unhandled default case")
+	}
+}
+
+func ParseGameMode(s string) (GameMode, error) {
+	for _, m := range []GameMode{ModeArcade, ModeEndless} {
+		if m.String() == s {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown game mode %q, want arcade or endless", s)
+}
+
+// Difficulty returns the level whose speeds apply on a level. Endless
+// boards are new every level, so they speed up at half the arcade's pace.
+func (m GameMode) Difficulty(level int) int {
+	if m == ModeEndless {
+		return (level + 1) / 2
+	}
+	return level
+}
+
+// BoardPool is where endless mode picks its boards from.
+type BoardPool struct {
+	Builtin   bool // the six arcade boards
+	Custom    bool // boards from maze files, the editor or the console
+	Generated bool // a new board from the generator
+}
+
+// ParseBoardPool reads a comma separated list of builtin, custom and
+// generated.
+func ParseBoardPool(list string) (BoardPool, error) {
+	var pool BoardPool
+	for _, name := range strings.Split(list, ",") {
+		switch strings.TrimSpace(name) {
+		case "builtin":
+			pool.Builtin = true
+		case "custom":
+			pool.Custom = true
+		case "generated":
+			pool.Generated = true
+		default:
+			return pool, fmt.Errorf("unknown board source %q, want one of %s", name, DefaultPool)
+		}
+	}
+	return pool, nil
+}
+
+// setMode starts over on the first level of a game mode.
+func (g *Game) setMode(mode GameMode, pool BoardPool) {
+	g.mode = mode
+	g.pool = pool
+	g.highScore = max(g.scores.Best(mode), g.player.score)
+	g.level = 1
+	g.boardNum = g.levelBoard()
+	g.mapBoard()
+	g.resetActors()
+	g.setPhase(PhaseStart, StartTime)
+}
+
+// difficulty is the level of the speed tables for the current level.
+func (g *Game) difficulty() int {
+	return g.mode.Difficulty(g.level)
+}
+
+// levelBoard picks the board for the current level.
+func (g *Game) levelBoard() int {
+	if g.mode == ModeArcade {
+		return boardForLevel(g.level)
+	}
+	return g.pickBoard()
+}
+
+// pickBoard picks one of the pool's sources, then a board from it other
+// than the current one where there is a choice. Generated boards replace
+// the previous generated board rather than piling up.
+func (g *Game) pickBoard() int {
+	var builtin, custom []int
+	for i, b := range g.boards {
+		switch {
+		case i == g.generated:
+		case !b.Custom():
+			builtin = append(builtin, i)
+		default:
+			custom = append(custom, i)
+		}
+	}
+
+	var sources [][]int
+	if g.pool.Builtin {
+		sources = append(sources, builtin)
+	}
+	if g.pool.Custom && len(custom) > 0 {
+		sources = append(sources, custom)
+	}
+	if g.pool.Generated {
+		sources = append(sources, nil)
+	}
+	if len(sources) == 0 {
+		return boardForLevel(g.level)
+	}
+
+	source := sources[g.rng.IntN(len(sources))]
+	if source == nil {
+		if n, ok := g.generateBoard(); ok {
+			return n
+		}
+		source = builtin
+	}
+	if len(source) > 1 {
+		source = slices.DeleteFunc(slices.Clone(source), func(n int) bool { return n == g.boardNum })
+	}
+	return source[g.rng.IntN(len(source))]
+}
+
+// generateBoard puts a new generated board in the endless mode's slot.
+func (g *Game) generateBoard() (int, bool) {
+	b, err := GenerateBoard(g.rng.Uint64())
+	if err != nil {
+		gameLog.Warn("generating an endless board", "err", err)
+		return 0, false
+	}
+	if g.generated < 0 {
+		g.boards = append(g.boards, b)
+		g.generated = len(g.boards) - 1
+	} else {
+		g.boards[g.generated] = b
+	}
+	return g.generated, true
+}
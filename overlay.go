@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Overlay is a debug layer drawn over the maze. Draw is called inside the
+// maze's camera, so it works in maze pixels: tile (x, y) starts at
+// (x*Pixel, y*Pixel). Add new layers to the overlays registry.
+type Overlay interface {
+	Name() string
+	Description() string
+	Draw(g *Game)
+}
+
+// overlays are the available layers, in the order of their toggle actions,
+// F2 and up by default.
+var overlays = [...]Overlay{
+	GridOverlay{},
+	TargetOverlay{},
+	PathOverlay{},
+	HoverOverlay{},
+	ModeOverlay{},
+	HouseOverlay{},
+	NodeOverlay{},
+	ZoneOverlay{},
+	SpeedOverlay{},
+}
+
+const (
+	NumOverlays     = len(overlays) // one toggle action each, see ActionOverlay
+	DefaultOverlays = "targets"     // enabled at startup
+)
+
+// Overlays tracks which layers are on. Layers are only drawn in debug mode,
+// which also shows a legend of the toggle keys.
+type Overlays struct {
+	layers  []Overlay
+	enabled []bool
+}
+
+func NewOverlays() *Overlays {
+	return &Overlays{layers: overlays[:], enabled: make([]bool, len(overlays))}
+}
+
+// OverlayNames returns the names of the available layers.
+func OverlayNames() []string {
+	names := make([]string, len(overlays))
+	for i, o := range overlays {
+		names[i] = o.Name()
+	}
+	return names
+}
+
+// Enable turns on a comma separated list of layers.
+func (o *Overlays) Enable(list string) error {
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		i := o.index(name)
+		if i < 0 {
+			return fmt.Errorf("unknown overlay %q, want one of: %s", name, strings.Join(OverlayNames(), ", "))
+		}
+		o.enabled[i] = true
+	}
+	return nil
+}
+
+// Toggle flips a layer by name and reports whether it exists.
+func (o *Overlays) Toggle(name string) bool {
+	i := o.index(name)
+	if i >= 0 {
+		o.enabled[i] = !o.enabled[i]
+	}
+	return i >= 0
+}
+
+func (o *Overlays) index(name string) int {
+	for i, layer := range o.layers {
+		if layer.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Update toggles layers with their actions while in debug mode.
+func (o *Overlays) Update(g *Game) {
+	if !g.debug {
+		return
+	}
+	for i := range o.layers {
+		if g.input.Pressed(ActionOverlay + Action(i)) {
+			o.enabled[i] = !o.enabled[i]
+		}
+	}
+}
+
+// Draw draws the enabled layers in maze coordinates.
+func (o *Overlays) Draw(g *Game) {
+	if !g.debug {
+		return
+	}
+	for i, layer := range o.layers {
+		if o.enabled[i] {
+			layer.Draw(g)
+		}
+	}
+}
+
+// DrawLegend lists the layers and their keys in screen coordinates, with
+// the frame rate.
+func (o *Overlays) DrawLegend(g *Game) {
+	if !g.debug {
+		return
+	}
+	rl.DrawFPS(10, 10)
+
+	const fontSize, lineHeight = 20, 22
+	w, _ := g.screenSize()
+	x := int32(w*Pixel - 330)
+	y := int32(TopPadding*Pixel + 8)
+	rl.DrawRectangle(x-8, y-4, 330, int32(len(o.layers))*lineHeight+8, rl.ColorAlpha(rl.Black, 0.7))
+	for i, layer := range o.layers {
+		color := rl.Gray
+		if o.enabled[i] {
+			color = rl.Green
+		}
+		key := bindingName(g.input.Binding(ActionOverlay + Action(i)))
+		text := fmt.Sprintf("%-3s %-8s %s", key, layer.Name(), layer.Description())
+		rl.DrawText(text, x, y+int32(i)*lineHeight, fontSize, color)
+	}
+}
+
+// tileCenter is the middle of a tile in maze pixels.
+func tileCenter(t Vec2i) rl.Vector2 {
+	return rl.Vector2{X: float32(t.X*Pixel) + Pixel/2, Y: float32(t.Y*Pixel) + Pixel/2}
+}
+
+// tileRect is the area of a tile in maze pixels.
+func tileRect(t Vec2i) rl.Rectangle {
+	return rl.NewRectangle(float32(t.X*Pixel), float32(t.Y*Pixel), Pixel, Pixel)
+}
+
+// entityCenter is the middle of a 16x16 sprite in maze pixels.
+func entityCenter(e *Entity) rl.Vector2 {
+	return rl.Vector2{X: e.pixel.X + Pixel, Y: e.pixel.Y + Pixel}
+}
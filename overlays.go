@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// PathSteps is how far ahead the path overlay predicts ghosts that steer
+// by straight line distance.
+const PathSteps = 12
+
+// GridOverlay is the checkerboard with tile numbers, for laying out boards.
+type GridOverlay struct{}
+
+func (GridOverlay) Name() string        { return "grid" }
+func (GridOverlay) Description() string { return "tile grid (L)" }
+func (GridOverlay) Draw(g *Game)        { g.drawCheckerBoard() }
+
+// TargetOverlay shows the tile each ghost is heading for.
+type TargetOverlay struct{}
+
+func (TargetOverlay) Name() string        { return "targets" }
+func (TargetOverlay) Description() string { return "ghost targets" }
+
+func (TargetOverlay) Draw(g *Game) {
+	for _, e := range g.ghosts {
+		if e.tile.X == 0 || e.tile.Y == 0 {
+			continue
+		}
+		target := tileCenter(g.maze.Clamp(e.target))
+		rl.DrawCircleV(target, Pixel, rl.ColorAlpha(e.color, 0.5))
+		rl.DrawLineEx(target, entityCenter(&e.Entity), 4, rl.ColorAlpha(e.color, 0.5))
+	}
+}
+
+// PathOverlay shows the tiles each ghost will move through: the shortest
+// path for eyes and navigating ghosts, a prediction of the arcade's
+// straight line choices for the others.
+type PathOverlay struct{}
+
+func (PathOverlay) Name() string        { return "paths" }
+func (PathOverlay) Description() string { return "planned ghost paths" }
+
+func (PathOverlay) Draw(g *Game) {
+	for i, e := range g.ghosts {
+		var path []Vec2i
+		switch {
+		case e.state == InHouse || e.state == Frightened:
+			continue
+		case e.state == Eaten || e.navigates():
+			path = g.graph.Path(g.maze.Wrap(e.tile), e.target)
+		default:
+			path = predictGhost(g, e, PathSteps)
+		}
+
+		// offset each ghost a little so overlapping paths stay visible
+		offset := float32(i*4 - 6)
+		for _, t := range path {
+			c := tileCenter(t)
+			rl.DrawRectangleV(rl.Vector2{X: c.X - 4 + offset, Y: c.Y - 4 + offset}, rl.Vector2{X: 8, Y: 8}, e.color)
+		}
+	}
+}
+
+// HoverOverlay names the tile under the mouse.
+type HoverOverlay struct{}
+
+func (HoverOverlay) Name() string        { return "hover" }
+func (HoverOverlay) Description() string { return "tile under mouse" }
+
+func (HoverOverlay) Draw(g *Game) {
+	t, ok := g.mouseTile()
+	if !ok {
+		return
+	}
+
+	rl.DrawRectangleLinesEx(tileRect(t), 2, rl.Yellow)
+	text := fmt.Sprintf("%d,%d %s", t.X, t.Y, g.maze[t.Y][t.X].Name())
+	if g.graph.IsIntersection(t) {
+		text += " intersection"
+	}
+	rl.DrawText(text, int32(t.X*Pixel), int32(t.Y*Pixel)-22, 20, rl.Yellow)
+}
+
+// ModeOverlay shows each ghost's state, the game's phase, the level time
+// and the scatter/chase timers.
+type ModeOverlay struct{}
+
+func (ModeOverlay) Name() string        { return "modes" }
+func (ModeOverlay) Description() string { return "ghost states, timers" }
+
+func (ModeOverlay) Draw(g *Game) {
+	for _, e := range g.ghosts {
+		rl.DrawText(e.state.String(), int32(e.pixel.X), int32(e.pixel.Y)-20, 20, e.color)
+	}
+
+	mode, left := modeAt(g.levelTime)
+	text := fmt.Sprintf("%s, time %.1fs, %s forever", g.phase, g.levelTime, mode)
+	if left >= 0 {
+		text = fmt.Sprintf("%s, time %.1fs, %s %.1fs", g.phase, g.levelTime, mode, left)
+	}
+	if fright := g.frightTime - g.now(); fright > 0 {
+		text += fmt.Sprintf(", frightened %.1fs", fright)
+	}
+	rl.DrawText(text, Pixel, int32(g.maze.Height()-1)*Pixel, 20, rl.White)
+}
+
+// HouseOverlay shows the dots eaten, which the ghosts' ExitHouse rules
+// count, and which ghosts in the house are ready to leave.
+type HouseOverlay struct{}
+
+func (HouseOverlay) Name() string        { return "house" }
+func (HouseOverlay) Description() string { return "house dot counters" }
+
+func (HouseOverlay) Draw(g *Game) {
+	x, y := int32(10*Pixel), int32(16*Pixel)
+	rl.DrawText(fmt.Sprintf("dots %d, left %d", g.dotsEaten, g.dotsLeft), x, y, 20, rl.White)
+	for _, e := range g.ghosts {
+		if e.state != InHouse && !e.leaving {
+			continue
+		}
+		y += 20
+		status := "waiting"
+		if e.leaving {
+			status = "leaving"
+		}
+		rl.DrawText(fmt.Sprintf("%s %s", g.tr(e.id.String()), g.tr(status)), x, y, 20, e.color)
+	}
+}
+
+// NodeOverlay marks the MazeGraph's nodes; intersections are larger.
+type NodeOverlay struct{}
+
+func (NodeOverlay) Name() string        { return "nodes" }
+func (NodeOverlay) Description() string { return "graph nodes" }
+
+func (NodeOverlay) Draw(g *Game) {
+	for _, n := range g.graph.Nodes() {
+		radius := float32(Pixel / 6)
+		if g.graph.IsIntersection(n) {
+			radius = Pixel / 3
+		}
+		rl.DrawCircleV(tileCenter(n), radius, rl.ColorAlpha(rl.Magenta, 0.7))
+	}
+}
+
+// noUpZones are the tiles where arcade Pac-Man's ghosts may not turn up
+// while chasing or scattering. Ms. Pac-Man dropped the rule and this game
+// does not enforce it; the zones are shown for comparing with the original.
+var noUpZones = []Vec2i{
+	{X: 12, Y: 11}, {X: 13, Y: 11}, {X: 14, Y: 11}, {X: 15, Y: 11},
+	{X: 12, Y: 23}, {X: 13, Y: 23}, {X: 14, Y: 23}, {X: 15, Y: 23},
+}
+
+// ZoneOverlay shows where actors slow down in the tunnels and the arcade's
+// no upward turn zones.
+type ZoneOverlay struct{}
+
+func (ZoneOverlay) Name() string        { return "zones" }
+func (ZoneOverlay) Description() string { return "tunnels, no up zones" }
+
+func (ZoneOverlay) Draw(g *Game) {
+	for y := range g.maze {
+		for x := range g.maze[y] {
+			t := Vec2i{X: x, Y: y}
+			if g.InTunnel(&Entity{tile: t}) {
+				rl.DrawRectangleRec(tileRect(t), rl.ColorAlpha(rl.Blue, 0.5))
+			} else if g.maze[y][x] == Tunnel {
+				rl.DrawRectangleRec(tileRect(t), rl.ColorAlpha(rl.SkyBlue, 0.3))
+			}
+		}
+	}
+
+	for _, t := range noUpZones {
+		rl.DrawRectangleLinesEx(tileRect(t), 2, rl.ColorAlpha(rl.Red, 0.7))
+	}
+}
+
+// SpeedOverlay shows each actor's current speed in arcade pixels per
+// second, to compare with the speed tables.
+type SpeedOverlay struct{}
+
+func (SpeedOverlay) Name() string        { return "speeds" }
+func (SpeedOverlay) Description() string { return "actor speeds" }
+
+func (SpeedOverlay) Draw(g *Game) {
+	p := g.player
+	drawSpeed(&p.Entity, p.calculateSpeed(g), rl.Yellow)
+	for _, e := range g.ghosts {
+		drawSpeed(&e.Entity, e.calculateSpeed(g), e.color)
+	}
+}
+
+func drawSpeed(e *Entity, speed float32, color rl.Color) {
+	// speeds are in arcade pixels per frame
+	text := fmt.Sprintf("%.0f", speed*60)
+	rl.DrawText(text, int32(e.pixel.X), int32(e.pixel.Y)+2*Pixel, 20, color)
+}
@@ -0,0 +1,102 @@
+package main
+
+// Phase is where the game is between the player's lives: the actors only
+// move while playing.
+type Phase int
+
+const (
+	PhaseStart    Phase = iota // PLAYER ONE and READY! while the intro plays
+	PhaseReady                 // READY! before each life and level
+	PhasePlaying               //
+	PhaseDying                 // caught, everything stops for a moment
+	PhaseGameOver              // waiting for a credit and start
+
+	StartTime    = 4.2 // seconds
+	ReadyTime    = 2.0
+	DeathTime    = 2.0
+	GameOverTime = 3.0 // before the demo starts over
+	MaxCredits   = 99
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseStart:
+		return "start"
+	case PhaseReady:
+		return "ready"
+	case PhasePlaying:
+		return "playing"
+	case PhaseDying:
+		return "dying"
+	case PhaseGameOver:
+		return "game over"
+	default:
+		panic("unhandled default case")
+	}
+}
+
+// setPhase enters a phase that lasts for a number of seconds of simulated
+// time.
+func (g *Game) setPhase(phase Phase, seconds float64) {
+	g.phase = phase
+	g.phaseEnd = g.now() + seconds
+}
+
+// stepPhase runs a tick of the phases in which the actors stand still.
+func (g *Game) stepPhase() {
+	if g.now() < g.phaseEnd {
+		return
+	}
+
+	switch g.phase {
+	case PhaseStart, PhaseReady:
+		g.setPhase(PhasePlaying, 0)
+		g.startTime = g.now()
+	case PhaseDying:
+		g.loseLife()
+	case PhaseGameOver:
+		// only the demo's bot plays again without a credit
+		if _, ok := g.player.controller.(*Bot); ok {
+			g.newGame()
+		}
+	}
+}
+
+// loseLife takes one of the player's lives after she was caught, and
+// either tries again on the same dots or ends the game.
+func (g *Game) loseLife() {
+	g.player.lives--
+	if g.player.lives <= 0 {
+		g.player.lives = 0
+		g.setPhase(PhaseGameOver, GameOverTime)
+		g.emit(GameOver{Score: g.player.score, Level: g.level})
+		g.recordScore()
+		return
+	}
+
+	dots, fruits := g.dotsEaten, g.fruitsSpawned
+	g.resetActors()
+	g.dotsEaten, g.fruitsSpawned = dots, fruits
+}
+
+// insertCoin adds a credit.
+func (g *Game) insertCoin() {
+	g.credits = min(g.credits+1, MaxCredits)
+}
+
+// pressStart starts a new game after game over, using up a credit.
+func (g *Game) pressStart() {
+	if g.phase != PhaseGameOver || g.credits == 0 {
+		return
+	}
+	g.credits--
+	g.newGame()
+}
+
+// newGame starts over on the first level with a fresh score and lives.
+func (g *Game) newGame() {
+	g.player.score = 0
+	g.player.lives = g.rules.Lives
+	g.setMode(g.mode, g.pool)
+	g.emit(GameStarted{})
+}
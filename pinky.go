@@ -37,11 +37,11 @@ func (b Pinky) Sprite() Vec2i {
 	return Vec2i{X: 520, Y: 80}
 }
 
-func (b Pinky) Chase(game *Game) Vec2i {
+func (b Pinky) Chase(game *Game, self *Ghost) Vec2i {
 	p := game.player
 	switch p.dir {
 	case Up:
-		if ChaseBug {
+		if game.rules.ChaseBug {
 			// original game had error in logic code
 			return p.tile.Add(-4, -4)
 		} else {
@@ -58,11 +58,11 @@ func (b Pinky) Chase(game *Game) Vec2i {
 	}
 }
 
-func (b Pinky) Scatter(game *Game) Vec2i {
+func (b Pinky) Scatter(game *Game, _ *Ghost) Vec2i {
 	return Vec2i{X: 1, Y: 1} // depends on board
 }
 
-func (b Pinky) ExitHouse(game *Game) bool {
+func (b Pinky) ExitHouse(game *Game, _ *Ghost) bool {
 	return game.levelTime > 1.0
 	//return true
 }
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"fmt"
-
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -10,18 +8,53 @@ const (
 	TunnelSpeedFactor = 0.5 // Pac-Man moves at 50% speed in tunnels
 	DotEatPause       = 1   // 1 frame pause when eating regular dots
 	PowerPelletPause  = 3   // 3 frames pause when eating power pellets
+
+	DotPoints   = 10
+	PowerPoints = 50
+	GhostPoints = 200 // doubles for each ghost eaten on one power pellet
+
+	StartingLives  = 3
+	ExtraLifeScore = 10000
 )
 
+// Cornering is how far, in pixels, from the center of a tile Ms. Packer may
+// turn. She can start a turn up to PreTurn pixels before the center and
+// still make it up to PostTurn pixels after, moving diagonally until she is
+// back on the new lane. Cutting corners like this is what lets her pull away
+// from ghosts around bends, since ghosts always turn at the tile center.
+// The game takes them from Rules, set in the settings file or by flags.
+type Cornering struct {
+	PreTurn  float32 `json:"pre_turn"`
+	PostTurn float32 `json:"post_turn"`
+}
+
+// DefaultCornering matches the arcade, which allows a few pixels each way.
+var DefaultCornering = Cornering{PreTurn: 4, PostTurn: 3}
+
+// MaxCornering is the widest window either way, half a tile.
+const MaxCornering = Size / 2
+
+// Clamp keeps both windows between 0 and MaxCornering.
+func (c Cornering) Clamp() Cornering {
+	return Cornering{
+		PreTurn:  min(max(c.PreTurn, 0), MaxCornering),
+		PostTurn: min(max(c.PostTurn, 0), MaxCornering),
+	}
+}
+
 type Player struct {
 	Entity
 	score       int
+	lives       int
 	pauseFrames int
 	isEatingDot bool
+	controller  Controller
+	cornering   Cornering
 }
 
-func NewPlayer() *Player {
-	startX := 13
-	startY := 23
+func NewPlayer(controller Controller, start Vec2i) *Player {
+	startX := start.X
+	startY := start.Y
 	shape := Left
 
 	return &Player{
@@ -41,10 +74,13 @@ func NewPlayer() *Player {
 			nextDir:   shape,
 			vel:       shape.Vector(),
 			nextVel:   shape.Vector(),
-			speedTime: rl.GetTime() + SpeedTime,
+			speedTime: SpeedTime,
 		},
 
-		score: 0,
+		score:      0,
+		lives:      StartingLives,
+		controller: controller,
+		cornering:  DefaultCornering,
 	}
 }
 
@@ -56,6 +92,9 @@ func (p *Player) Update(game *Game) {
 		return
 	}
 
+	// --- Cornering: turn a few pixels before or after the center ---
+	p.turnCorner(game)
+
 	// --- Intersection and Direction Change Logic ---
 	if p.pixelsMoved >= Size {
 		// Update tile position based on the last move
@@ -69,8 +108,8 @@ func (p *Player) Update(game *Game) {
 			p.vel = p.nextVel
 		} else if game.InTunnel(&p.Entity) {
 			if p.tile.X < 0 {
-				p.tile.X = GameWidth - 1
-			} else if p.tile.X >= GameWidth-1 {
+				p.tile.X = game.maze.Width() - 1
+			} else if p.tile.X >= game.maze.Width()-1 {
 				p.tile.X = 0
 			}
 		} else if !p.canMove(game.maze, p.vel) {
@@ -92,25 +131,79 @@ func (p *Player) Update(game *Game) {
 	p.speedPixels += speed
 	if p.speedTime-game.levelTime <= 0 {
 		p.speedTime = game.levelTime + SpeedTime
-		fmt.Printf("player moving %0.3f/s\n", p.speedPixels)
+		timingLog.Debug("speed", "entity", "player", "pixels_per_sec", p.speedPixels)
 		p.speedPixels = 0
 	}
 
 	// --- Eat Dots (only check once per tile entry) ---
-	if p.tile.InMaze() && !p.isEatingDot {
+	if game.maze.Contains(p.tile) && !p.isEatingDot {
 		tile := game.maze[p.tile.Y][p.tile.X]
 		// Check for power pellet first
 		if tile == Power {
 			game.maze[p.tile.Y][p.tile.X] = Empty
+			p.addScore(game, PowerPoints)
 			p.pauseFrames = PowerPelletPause
 			p.isEatingDot = true
 			game.setGhostMode(Frightened)
+			game.dotsLeft--
+			game.emit(PelletEaten{Tile: p.tile})
 		} else if tile == Dot {
 			game.maze[p.tile.Y][p.tile.X] = Empty
 			game.dotsEaten++
+			p.addScore(game, DotPoints)
 			p.pauseFrames = DotEatPause
 			p.isEatingDot = true
+			game.dotsLeft--
+			game.emit(DotEaten{Tile: p.tile})
+		}
+	}
+}
+
+// turnCorner takes a perpendicular turn early (pre-turn) or late (post-turn)
+// when the player is within the cornering window of a tile center. The
+// distance to the center becomes a corner offset on the old axis that
+// Entity.move works off while she already moves along the new one.
+func (p *Player) turnCorner(game *Game) {
+	if p.vel.IsZero() || p.nextVel.IsZero() || p.vel.X*p.nextVel.X+p.vel.Y*p.nextVel.Y != 0 {
+		return // not moving, or not a perpendicular turn
+	}
+
+	remaining := Size - p.pixelsMoved
+	if remaining > 0 && remaining <= p.cornering.PreTurn {
+		// pre-turn around the tile we are about to reach
+		corner := game.maze.Wrap(p.tile.Add(p.vel.X, p.vel.Y))
+		if !p.canMoveFrom(game.maze, corner, p.nextVel) {
+			return
+		}
+
+		p.corner = rl.Vector2{X: float32(-p.vel.X) * remaining, Y: float32(-p.vel.Y) * remaining}
+		p.tile = corner
+		p.isEatingDot = false
+	} else if p.pixelsMoved > 0 && p.pixelsMoved <= p.cornering.PostTurn {
+		// post-turn around the tile we just passed
+		if !p.canMove(game.maze, p.nextVel) {
+			return
 		}
+
+		p.corner = rl.Vector2{X: float32(p.vel.X) * p.pixelsMoved, Y: float32(p.vel.Y) * p.pixelsMoved}
+	} else {
+		return
+	}
+
+	p.dir = p.nextDir
+	p.vel = p.nextVel
+	p.pixelsMoved = 0
+}
+
+func (p *Player) addScore(game *Game, points int) {
+	if bonus := game.rules.BonusLife; bonus > 0 && p.score < bonus && p.score+points >= bonus {
+		p.lives++
+		game.emit(ExtraLife{Lives: p.lives})
+	}
+
+	p.score += points
+	if p.score > game.highScore {
+		game.highScore = p.score
 	}
 }
 
@@ -142,10 +235,10 @@ func (p *Player) updateFrame() {
 func (p *Player) calculateSpeed(game *Game) float32 {
 	var speed float32
 
-	if game.frightTime-rl.GetTime() > 0 {
-		speed = playerFrightSpeed(game.level)
+	if game.frightTime-game.now() > 0 {
+		speed = playerFrightSpeed(game.difficulty())
 	} else {
-		speed = playerSpeed(game.level)
+		speed = playerSpeed(game.difficulty())
 	}
 
 	// Apply tunnel speed reduction
@@ -153,18 +246,22 @@ func (p *Player) calculateSpeed(game *Game) float32 {
 		speed *= TunnelSpeedFactor
 	}
 
-	return speed
+	return speed * game.speedScale
 }
 
 func (p *Player) canMove(maze Maze, dir Vec2i) bool {
+	return p.canMoveFrom(maze, p.tile, dir)
+}
+
+func (p *Player) canMoveFrom(maze Maze, tile Vec2i, dir Vec2i) bool {
 	if dir.X == 0 && dir.Y == 0 {
 		return false
 	}
 
-	nextTile := p.tile.Add(dir.X, dir.Y)
+	nextTile := tile.Add(dir.X, dir.Y)
 
 	// Check for moving off the map boundaries (non-tunnel)
-	if nextTile.X < 0 || nextTile.X >= GameWidth || nextTile.Y < 0 || nextTile.Y >= GameHeight {
+	if !maze.Contains(nextTile) {
 		return false
 	}
 
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// cornerGame is a maze with a single bend: a corridor running right along
+// row 1 that turns down at column 4 and carries on one more tile.
+//
+//	#######
+//	#.....#
+//	####.##
+//	####.##
+//	#######
+func cornerGame(cornering Cornering) *Game {
+	maze := make(Maze, GameHeight)
+	for y := range maze {
+		maze[y] = make([]Tile, GameWidth)
+	}
+	for x := 1; x <= 5; x++ {
+		maze[1][x] = Empty
+	}
+	for y := 2; y <= 3; y++ {
+		maze[y][4] = Empty
+	}
+
+	p := &Player{
+		Entity: Entity{
+			pixel:   rl.Vector2{X: Pixel, Y: Pixel},
+			tile:    Vec2i{X: 1, Y: 1},
+			dir:     Right,
+			vel:     Right.Vector(),
+			nextDir: Down,
+			nextVel: Down.Vector(),
+		},
+		cornering: cornering,
+	}
+	return &Game{maze: maze, level: 1, speedScale: 1, player: p}
+}
+
+// runUntil updates the player until done says so, returning the tick it
+// did on.
+func runUntil(t *testing.T, g *Game, done func(p *Player) bool) int {
+	t.Helper()
+	for tick := 1; tick <= 100; tick++ {
+		g.player.Update(g)
+		if done(g.player) {
+			return tick
+		}
+	}
+	t.Fatal("the player never got there")
+	return 0
+}
+
+// turned is when she is heading down and back on the new lane.
+func turned(p *Player) bool {
+	return p.vel == Down.Vector() && p.corner.X == 0 && p.corner.Y == 0
+}
+
+func TestTurnCorner(t *testing.T) {
+	tests := []struct {
+		name      string
+		cornering Cornering
+		want      int
+	}{
+		// three tiles of 6 ticks at 1.5 pixels a tick, turning on the
+		// tick she reaches the center
+		{"center", Cornering{}, 19},
+		// starts the turn on tick 16 with 3.5 pixels to go, then cuts
+		// the corner diagonally
+		{"pre-turn", DefaultCornering, 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := cornerGame(tt.cornering)
+			if got := runUntil(t, g, turned); got != tt.want {
+				t.Errorf("turned on tick %d, want %d", got, tt.want)
+			}
+			if g.player.tile != (Vec2i{X: 4, Y: 1}) {
+				t.Errorf("turned at %s, want (4, 1)", g.player.tile)
+			}
+		})
+	}
+}
+
+// A turn asked for just after passing the center is still taken within
+// the post-turn window, and missed without one.
+func TestPostTurn(t *testing.T) {
+	tests := []struct {
+		name      string
+		cornering Cornering
+		want      Vec2i // where she stops
+	}{
+		{"post-turn", DefaultCornering, Vec2i{X: 4, Y: 3}},
+		{"too late", Cornering{}, Vec2i{X: 5, Y: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := cornerGame(tt.cornering)
+			g.player.nextDir, g.player.nextVel = None, Vec2i{}
+			runUntil(t, g, func(p *Player) bool {
+				return p.tile == Vec2i{X: 4, Y: 1} && p.pixelsMoved > 0
+			})
+
+			g.player.nextDir, g.player.nextVel = Down, Down.Vector()
+			runUntil(t, g, func(p *Player) bool { return p.vel.IsZero() })
+			if g.player.tile != tt.want {
+				t.Errorf("stopped at %s, want %s", g.player.tile, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreTurnBeatsCenterTurn(t *testing.T) {
+	down := func(p *Player) bool { return p.tile == Vec2i{X: 4, Y: 3} }
+
+	ghostStyle := runUntil(t, cornerGame(Cornering{}), down)
+	preTurn := runUntil(t, cornerGame(DefaultCornering), down)
+	if preTurn >= ghostStyle {
+		t.Errorf("pre-turning reached the end of the bend on tick %d, turning at the center on tick %d", preTurn, ghostStyle)
+	}
+}
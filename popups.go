@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const PopupDuration = 1.5 // simulated seconds
+
+// Popups shows the points scored for eating a ghost or a fruit where it
+// happened.
+type Popups struct {
+	items []popup
+}
+
+type popup struct {
+	text  string
+	tile  Vec2i
+	until float64
+	color rl.Color
+}
+
+func (p *Popups) HandleEvent(g *Game, _ int, e Event) {
+	switch e := e.(type) {
+	case GhostEaten:
+		p.add(g, e.Points, e.Tile, rl.SkyBlue)
+	case FruitEaten:
+		p.add(g, e.Points, e.Tile, rl.Pink)
+	}
+}
+
+func (p *Popups) add(g *Game, points int, tile Vec2i, color rl.Color) {
+	p.items = append(p.items, popup{
+		text:  fmt.Sprintf("%d", points),
+		tile:  tile,
+		until: g.now() + PopupDuration,
+		color: color,
+	})
+}
+
+// Update drops popups that have been shown long enough.
+func (p *Popups) Update(g *Game) {
+	now := g.now()
+	items := p.items[:0]
+	for _, item := range p.items {
+		if item.until > now {
+			items = append(items, item)
+		}
+	}
+	p.items = items
+}
+
+func (p *Popups) Draw(g *Game) {
+	for _, item := range p.items {
+		g.drawTextAligned(item.text, tileCenter(item.tile).X, float32(item.tile.Y*Pixel), AlignCenter, item.color)
+	}
+}
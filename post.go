@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Effect is a post-processing pass over the scaled picture: a fragment
+// shader in ShaderDir taking the picture as texture0, its arcade size as
+// sourceSize, its window size as resolution, and a float uniform per
+// parameter. Add new effects to the effects registry.
+type Effect struct {
+	Name        string
+	Description string
+	Params      []EffectParam
+}
+
+type EffectParam struct {
+	Name     string
+	Default  float32
+	Min, Max float32
+}
+
+// effects are the available passes, in the order a chain usually wants
+// them: the picture's own look, then the tube's.
+var effects = []*Effect{
+	{Name: "bleed", Description: "composite color bleed", Params: []EffectParam{
+		{Name: "amount", Default: 0.5, Min: 0, Max: 1},
+		{Name: "width", Default: 1, Min: 0, Max: 4},
+	}},
+	{Name: "bloom", Description: "glow around bright colors", Params: []EffectParam{
+		{Name: "amount", Default: 0.8, Min: 0, Max: 4},
+		{Name: "threshold", Default: 0.4, Min: 0, Max: 1},
+		{Name: "radius", Default: 2, Min: 0, Max: 8},
+	}},
+	{Name: "scanlines", Description: "dark gaps between pixel rows", Params: []EffectParam{
+		{Name: "strength", Default: 0.4, Min: 0, Max: 1},
+		{Name: "sharpness", Default: 2, Min: 0.5, Max: 8},
+	}},
+	{Name: "mask", Description: "phosphor stripes", Params: []EffectParam{
+		{Name: "strength", Default: 0.3, Min: 0, Max: 1},
+		{Name: "size", Default: 1, Min: 1, Max: 4},
+	}},
+	{Name: "curvature", Description: "curved glass and dark corners", Params: []EffectParam{
+		{Name: "amount", Default: 0.15, Min: 0, Max: 1},
+		{Name: "vignette", Default: 0.3, Min: 0, Max: 1},
+	}},
+}
+
+const (
+	ShaderDir      = "shaders"
+	DefaultEffects = "none"
+)
+
+// EffectPreset is a named chain of effects.
+type EffectPreset struct {
+	Name    string
+	Effects string
+}
+
+// EffectPresets are the chains the settings menu offers.
+var EffectPresets = []EffectPreset{
+	{"off", "none"},
+	{"scanlines", "scanlines"},
+	{"crt", "scanlines,mask,curvature"},
+	{"glow", "bloom,scanlines,mask,curvature"},
+	{"composite", "bleed,bloom,scanlines,mask,curvature"},
+}
+
+// EffectNames returns the names of the available effects.
+func EffectNames() []string {
+	names := make([]string, len(effects))
+	for i, e := range effects {
+		names[i] = e.Name
+	}
+	return names
+}
+
+func findEffect(name string) *Effect {
+	for _, e := range effects {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// PostChain runs the scaled picture through a list of effects, ping-ponging
+// between two window sized render textures.
+type PostChain struct {
+	passes  []*postPass
+	values  map[string]float32 // by effect.param, defaults until set
+	targets [2]rl.RenderTexture2D
+	width   int32 // of the targets
+	height  int32
+}
+
+type postPass struct {
+	effect *Effect
+	shader rl.Shader
+}
+
+func NewPostChain() *PostChain {
+	p := &PostChain{values: map[string]float32{}}
+	for _, e := range effects {
+		for _, param := range e.Params {
+			p.values[e.Name+"."+param.Name] = param.Default
+		}
+	}
+	return p
+}
+
+// Set replaces the chain with a comma separated list of effects, applied in
+// the order given, or none.
+func (p *PostChain) Set(list string) error {
+	var chain []*Effect
+	if list != "none" && list != "" {
+		for _, name := range strings.Split(list, ",") {
+			e := findEffect(strings.TrimSpace(name))
+			if e == nil {
+				return fmt.Errorf("unknown effect %q, want none or %s", name, strings.Join(EffectNames(), ", "))
+			}
+			chain = append(chain, e)
+		}
+	}
+
+	for _, pass := range p.passes {
+		rl.UnloadShader(pass.shader)
+	}
+	p.passes = nil
+	for _, e := range chain {
+		shader := rl.LoadShader("", filepath.Join(ShaderDir, e.Name+".fs"))
+		p.passes = append(p.passes, &postPass{effect: e, shader: shader})
+	}
+	return nil
+}
+
+// String is the chain as Set takes it.
+func (p *PostChain) String() string {
+	if len(p.passes) == 0 {
+		return "none"
+	}
+	names := make([]string, len(p.passes))
+	for i, pass := range p.passes {
+		names[i] = pass.effect.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// SetParam sets an effect's parameter, named effect.param, clamped to its
+// range.
+func (p *PostChain) SetParam(name string, value float32) error {
+	effect, param, _ := strings.Cut(name, ".")
+	e := findEffect(effect)
+	if e == nil {
+		return fmt.Errorf("unknown effect %q", effect)
+	}
+	i := slices.IndexFunc(e.Params, func(ep EffectParam) bool { return ep.Name == param })
+	if i < 0 {
+		return fmt.Errorf("%s has no parameter %q", effect, param)
+	}
+	p.values[name] = min(max(value, e.Params[i].Min), e.Params[i].Max)
+	return nil
+}
+
+// SetParams sets a comma separated list of effect.param=value.
+func (p *PostChain) SetParams(list string) error {
+	if list == "" {
+		return nil
+	}
+	for _, item := range strings.Split(list, ",") {
+		name, text, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return fmt.Errorf("effect parameter %q, want effect.param=value", item)
+		}
+		value, err := strconv.ParseFloat(text, 32)
+		if err != nil {
+			return err
+		}
+		if err := p.SetParam(name, float32(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EffectParamNames returns every effect.param.
+func EffectParamNames() []string {
+	var names []string
+	for _, e := range effects {
+		for _, param := range e.Params {
+			names = append(names, e.Name+"."+param.Name)
+		}
+	}
+	return names
+}
+
+// Describe lists the chain's parameters and their values.
+func (p *PostChain) Describe() string {
+	lines := []string{"effects: " + p.String()}
+	for _, pass := range p.passes {
+		var params []string
+		for _, param := range pass.effect.Params {
+			name := pass.effect.Name + "." + param.Name
+			params = append(params, fmt.Sprintf("%s=%g (%g-%g)", name, p.values[name], param.Min, param.Max))
+		}
+		lines = append(lines, strings.Join(params, "  "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Apply scales the arcade picture by a whole number and runs it through the
+// chain, returning the render texture holding the result.
+func (p *PostChain) Apply(src rl.Texture2D, scale float32) rl.RenderTexture2D {
+	width, height := int32(float32(src.Width)*scale), int32(float32(src.Height)*scale)
+	p.resize(width, height)
+
+	full := rl.NewRectangle(0, 0, float32(width), float32(height))
+	flipped := rl.NewRectangle(0, 0, float32(width), -float32(height)) // render textures are upside down
+	sourceSize := []float32{float32(src.Width), float32(src.Height)}
+	resolution := []float32{float32(width), float32(height)}
+
+	rl.BeginTextureMode(p.targets[0])
+	rl.ClearBackground(rl.Black)
+	srcRect := rl.NewRectangle(0, 0, float32(src.Width), -float32(src.Height))
+	rl.DrawTexturePro(src, srcRect, full, rl.Vector2{}, 0, rl.White)
+	rl.EndTextureMode()
+
+	for i, pass := range p.passes {
+		in, out := p.targets[i%2], p.targets[(i+1)%2]
+		shader := pass.shader
+		rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "sourceSize"), sourceSize, rl.ShaderUniformVec2)
+		rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "resolution"), resolution, rl.ShaderUniformVec2)
+		for _, param := range pass.effect.Params {
+			value := []float32{p.values[pass.effect.Name+"."+param.Name]}
+			rl.SetShaderValue(shader, rl.GetShaderLocation(shader, param.Name), value, rl.ShaderUniformFloat)
+		}
+
+		rl.BeginTextureMode(out)
+		rl.ClearBackground(rl.Black)
+		rl.BeginShaderMode(shader)
+		rl.DrawTexturePro(in.Texture, flipped, full, rl.Vector2{}, 0, rl.White)
+		rl.EndShaderMode()
+		rl.EndTextureMode()
+	}
+	return p.targets[len(p.passes)%2]
+}
+
+// Active reports whether there are any effects to apply.
+func (p *PostChain) Active() bool {
+	return len(p.passes) > 0
+}
+
+// resize makes targets of a size, or frees them for 0x0.
+func (p *PostChain) resize(width, height int32) {
+	if width == p.width && height == p.height {
+		return
+	}
+	for i, t := range p.targets {
+		if t.ID != 0 {
+			rl.UnloadRenderTexture(t)
+		}
+		p.targets[i] = rl.RenderTexture2D{}
+		if width > 0 && height > 0 {
+			p.targets[i] = rl.LoadRenderTexture(width, height)
+		}
+	}
+	p.width, p.height = width, height
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// RebindScreen lists every action with its keys and buttons. Up and down
+// pick an action, enter (or the start action) waits for the next key or
+// gamepad button and adds it, backspace waits for one to replace the keys
+// (or buttons) with, delete restores the default and the rebind action
+// closes the screen. A key or button bound to another action is refused.
+// Changes are saved as they are made.
+type RebindScreen struct {
+	selected  int
+	waiting   bool
+	replace   bool   // the key or button waited for replaces the others
+	refused   string // key or button just refused, bound to refusedBy
+	refusedBy Action
+}
+
+const RebindRowHeight = Pixel * 9 / 8 // screen units per action
+
+func NewRebindScreen() *RebindScreen {
+	return &RebindScreen{}
+}
+
+func (r *RebindScreen) Update(g *Game) {
+	in := g.input
+	action := Action(r.selected)
+
+	if r.waiting {
+		b := in.Binding(action)
+		if key := rl.GetKeyPressed(); key != 0 {
+			if r.replace {
+				b.Keys = nil
+			}
+			if !slices.Contains(b.Keys, key) {
+				b.Keys = append(b.Keys, key)
+			}
+		} else if button := rl.GetGamepadButtonPressed(); button > 0 {
+			if r.replace {
+				b.Buttons = nil
+			}
+			if !slices.Contains(b.Buttons, button) {
+				b.Buttons = append(b.Buttons, button)
+			}
+		} else {
+			return
+		}
+
+		r.waiting = false
+		r.bind(in, action, b)
+		return
+	}
+
+	switch {
+	case in.Pressed(ActionRebind):
+		g.rebind = nil
+	case in.Pressed(ActionUp):
+		r.selected = (r.selected + NumActions - 1) % NumActions
+		r.refused = ""
+	case in.Pressed(ActionDown):
+		r.selected = (r.selected + 1) % NumActions
+		r.refused = ""
+	case rl.IsKeyPressed(rl.KeyEnter) || in.Pressed(ActionStart):
+		r.waiting, r.replace, r.refused = true, false, ""
+	case rl.IsKeyPressed(rl.KeyBackspace):
+		r.waiting, r.replace, r.refused = true, true, ""
+	case rl.IsKeyPressed(rl.KeyDelete):
+		r.refused = ""
+		r.bind(in, action, DefaultBindings()[action])
+	}
+}
+
+// bind sets an action's binding, unless another action already has one of
+// its keys or buttons.
+func (r *RebindScreen) bind(in *Input, a Action, b Binding) {
+	if other, name, ok := in.Conflict(a, b); ok {
+		r.refused, r.refusedBy = name, other
+		return
+	}
+	in.SetBinding(a, b)
+	r.save(in)
+}
+
+func (r *RebindScreen) save(in *Input) {
+	if err := in.Save(); err != nil {
+		gameLog.Error("saving input bindings", "err", err)
+	}
+}
+
+func (r *RebindScreen) Draw(g *Game) {
+	w, h := g.screenSize()
+	rl.DrawRectangle(0, 0, int32(w*Pixel), int32(h*Pixel), rl.ColorAlpha(rl.Black, 0.9))
+
+	g.drawTextAligned(g.tr("CONTROLS"), float32(w*Pixel/2), 3*Pixel, AlignCenter, rl.Yellow)
+
+	// the actions that fit, scrolled to keep the selected one in the middle
+	rows := min((h-9)*Pixel/RebindRowHeight, NumActions)
+	top := min(max(r.selected-rows/2, 0), NumActions-rows)
+	for i := top; i < top+rows; i++ {
+		a := Action(i)
+		color := rl.White
+		if i == r.selected {
+			color = rl.Yellow
+		}
+
+		y, offset := 6, (i-top)*RebindRowHeight
+		g.drawText(g.tr(a.String()), 1, y, offset, color)
+
+		if i == r.selected && r.waiting {
+			g.drawText(g.tr("PRESS A KEY"), 14, y, offset, rl.Red)
+		} else {
+			g.drawText(bindingName(g.input.Binding(a)), 14, y, offset, color)
+		}
+	}
+
+	if r.refused != "" {
+		refused := fmt.Sprintf(g.tr("%s IS FOR %s"), r.refused, g.tr(r.refusedBy.String()))
+		g.drawTextAligned(refused, float32(w*Pixel/2), float32((h-3)*Pixel), AlignCenter, rl.Red)
+	} else {
+		g.drawTextAligned(g.tr("ENTER ADD  BKSP REPLACE"), float32(w*Pixel/2), float32((h-3)*Pixel), AlignCenter, rl.Gray)
+	}
+	g.drawTextAligned(g.tr("DEL DEFAULT"), float32(w*Pixel/2), float32((h-2)*Pixel), AlignCenter, rl.Gray)
+}
+
+func bindingName(b Binding) string {
+	var names []string
+	for _, key := range b.Keys {
+		names = append(names, keyName(key))
+	}
+	for _, button := range b.Buttons {
+		names = append(names, buttonName(button))
+	}
+	return strings.Join(names, " ")
+}
+
+func buttonName(button int32) string {
+	return fmt.Sprintf("PAD%d", button)
+}
+
+func keyName(key int32) string {
+	switch {
+	case key >= rl.KeyA && key <= rl.KeyZ, key >= rl.KeyZero && key <= rl.KeyNine:
+		return string(rune(key))
+	case key >= rl.KeyF1 && key <= rl.KeyF12:
+		return fmt.Sprintf("F%d", key-rl.KeyF1+1)
+	}
+
+	switch key {
+	case rl.KeyUp:
+		return "UP"
+	case rl.KeyDown:
+		return "DOWN"
+	case rl.KeyLeft:
+		return "LEFT"
+	case rl.KeyRight:
+		return "RIGHT"
+	case rl.KeySpace:
+		return "SPACE"
+	case rl.KeyEnter:
+		return "ENTER"
+	case rl.KeyTab:
+		return "TAB"
+	case rl.KeyBackspace:
+		return "BKSP"
+	case rl.KeyGrave:
+		return "TILDE"
+	case rl.KeyMinus:
+		return "MINUS"
+	case rl.KeyEqual:
+		return "EQUAL"
+	case rl.KeyPeriod:
+		return "PERIOD"
+	case rl.KeyLeftShift, rl.KeyRightShift:
+		return "SHIFT"
+	case rl.KeyLeftControl, rl.KeyRightControl:
+		return "CTRL"
+	default:
+		return fmt.Sprintf("KEY%d", key)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplayKeepsSeed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "game.jsonl")
+	r, err := NewRecorder(file, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.HandleEvent(nil, 10, PlayerTurned{Dir: Up, Tick: 10})
+	r.HandleEvent(nil, 20, PlayerTurned{Dir: Left, Tick: 20})
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := LoadReplay(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.seed != 42 {
+		t.Errorf("seed %d, want 42", replay.seed)
+	}
+	if got := replay.NextDirection(&Game{tick: 20}); got != Left {
+		t.Errorf("direction on tick 20 %s, want %s", got, Left)
+	}
+}
+
+// Replays recorded before the seed was saved start with a step.
+func TestReplayWithoutSeed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "old.jsonl")
+	if err := os.WriteFile(file, []byte(`{"tick":5,"dir":"Down"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := LoadReplay(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.seed != 0 || len(replay.steps) != 1 {
+		t.Errorf("seed %d and %d steps, want 0 and 1", replay.seed, len(replay.steps))
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	ScoresFile = "scores.json"
+	MaxScores  = 10 // per game mode
+)
+
+// Score is one finished game on the high score table.
+type Score struct {
+	Score int    `json:"score"`
+	Level int    `json:"level"`
+	Board string `json:"board"` // the last board played
+	Date  string `json:"date"`
+}
+
+// HighScores keeps the best scores of each game mode, highest first, in a
+// JSON file next to the input bindings.
+type HighScores struct {
+	file  string
+	modes map[string][]Score
+}
+
+// ScoresPath returns where the high scores are kept.
+func ScoresPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ScoresFile
+	}
+	return filepath.Join(dir, "mspackerfan", ScoresFile)
+}
+
+// LoadHighScores reads the high score file. A missing file is an empty
+// table; a broken one is reported and then overwritten on the next save.
+func LoadHighScores(file string) *HighScores {
+	h := &HighScores{file: file, modes: map[string][]Score{}}
+	if file == "" {
+		return h
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "loading high scores: %v\n", err)
+		}
+		return h
+	}
+	if err := json.Unmarshal(data, &h.modes); err != nil {
+		fmt.Fprintf(os.Stderr, "loading high scores: %v\n", err)
+	}
+	return h
+}
+
+// Best returns a mode's high score, 0 when it has none.
+func (h *HighScores) Best(mode GameMode) int {
+	if scores := h.modes[mode.String()]; len(scores) > 0 {
+		return scores[0].Score
+	}
+	return 0
+}
+
+// Add puts a score on a mode's table and returns its place, from 1, or 0
+// when it didn't make the table.
+func (h *HighScores) Add(mode GameMode, s Score) int {
+	scores := h.modes[mode.String()]
+	// below any equal score, which was there first
+	i := slices.IndexFunc(scores, func(e Score) bool { return e.Score < s.Score })
+	if i < 0 {
+		i = len(scores)
+	}
+	if i >= MaxScores {
+		return 0
+	}
+	scores = slices.Insert(scores, i, s)
+	h.modes[mode.String()] = scores[:min(len(scores), MaxScores)]
+	return i + 1
+}
+
+// Save writes the table to the high score file.
+func (h *HighScores) Save() error {
+	if h.file == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(h.modes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(h.file, data, 0o644)
+}
+
+// Table lists a mode's scores, one per line.
+func (h *HighScores) Table(mode GameMode) string {
+	scores := h.modes[mode.String()]
+	if len(scores) == 0 {
+		return fmt.Sprintf("no %s scores yet", mode)
+	}
+	lines := []string{fmt.Sprintf("%s high scores", mode)}
+	for i, s := range scores {
+		lines = append(lines, fmt.Sprintf("%2d %7d  level %-3d %-12s %s", i+1, s.Score, s.Level, s.Board, s.Date))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// recordScore puts the player's game on the high score table. Games played
+// by the bot or a replay aren't recorded.
+func (g *Game) recordScore() {
+	if _, ok := g.player.controller.(Human); !ok || g.player.score == 0 {
+		return
+	}
+
+	place := g.scores.Add(g.mode, Score{
+		Score: g.player.score,
+		Level: g.level,
+		Board: g.board().Name,
+		Date:  time.Now().Format(time.DateOnly),
+	})
+	if place == 0 {
+		return
+	}
+	if err := g.scores.Save(); err != nil {
+		gameLog.Error("saving high scores", "err", err)
+		return
+	}
+	gameLog.Info("high score", "mode", g.mode, "place", place, "score", g.player.score)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHighScoresAdd(t *testing.T) {
+	h := LoadHighScores("")
+	for i, tt := range []struct {
+		score, place int
+	}{
+		{500, 1},
+		{900, 1},
+		{700, 2},
+		{700, 3}, // below the 700 that was there first
+		{100, 5},
+	} {
+		if got := h.Add(ModeArcade, Score{Score: tt.score, Level: i + 1}); got != tt.place {
+			t.Errorf("%d: place %d, want %d", tt.score, got, tt.place)
+		}
+	}
+
+	// the games by level, which is the order they were added in
+	var got []int
+	for _, s := range h.modes[ModeArcade.String()] {
+		got = append(got, s.Level)
+	}
+	if want := []int{2, 3, 4, 1, 5}; !slices.Equal(got, want) {
+		t.Errorf("games %v, want %v", got, want)
+	}
+	if best := h.Best(ModeEndless); best != 0 {
+		t.Errorf("endless best %d, want 0: modes share a table", best)
+	}
+}
+
+func TestHighScoresFull(t *testing.T) {
+	h := LoadHighScores("")
+	for i := range MaxScores {
+		h.Add(ModeEndless, Score{Score: 1000 - i*100})
+	}
+
+	if place := h.Add(ModeEndless, Score{Score: 100}); place != 0 {
+		t.Errorf("tying the lowest score: place %d, want 0", place)
+	}
+	if place := h.Add(ModeEndless, Score{Score: 550}); place != 6 {
+		t.Errorf("550: place %d, want 6", place)
+	}
+
+	scores := h.modes[ModeEndless.String()]
+	if len(scores) != MaxScores {
+		t.Fatalf("%d scores, want %d", len(scores), MaxScores)
+	}
+	if last := scores[MaxScores-1].Score; last != 200 {
+		t.Errorf("last score %d, want 200 after 100 dropped off", last)
+	}
+}
+
+func TestParseBoardPool(t *testing.T) {
+	for _, tt := range []struct {
+		list    string
+		want    BoardPool
+		wantErr bool
+	}{
+		{list: "builtin", want: BoardPool{Builtin: true}},
+		{list: DefaultPool, want: BoardPool{Builtin: true, Custom: true, Generated: true}},
+		{list: " custom , generated ", want: BoardPool{Custom: true, Generated: true}},
+		{list: "generated,generated", want: BoardPool{Generated: true}},
+		{list: "builtin,arcade", wantErr: true},
+		{list: "", wantErr: true},
+	} {
+		got, err := ParseBoardPool(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: error %v, want error %t", tt.list, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("%q: %+v, want %+v", tt.list, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	MaxScale      = 8
+	MonitorMargin = 80 // pixels left for the taskbar and title bar when fitting the window
+)
+
+// screenView is the camera for drawing in screen units without a maze
+// offset, e.g. the score and the debug legend.
+var screenView = rl.Camera2D{Zoom: 1}
+
+// Screen renders the game at the arcade's resolution, Size pixels a tile,
+// to a texture that is scaled by whole numbers to fit the window, centered
+// with black bars around it, and optionally turned a quarter clockwise like
+// a cabinet with its monitor on its side.
+//
+// Drawing code works in screen units, Zoom of them per arcade pixel, which
+// keeps sub-pixel movement and lets the debug tools draw finer than the
+// game. Begin starts the game's pass into the texture, Present shows it and
+// starts the tools' pass at the window's resolution; Camera takes screen
+// units to whichever pass is running.
+type Screen struct {
+	target  rl.RenderTexture2D
+	width   int32 // arcade pixels
+	height  int32
+	scale   int // window size in multiples of the arcade screen, 0 to fit the monitor
+	rotated bool
+	window  bool       // in the tools' pass
+	post    *PostChain // effects over the scaled picture
+}
+
+func NewScreen() *Screen {
+	return &Screen{post: NewPostChain()}
+}
+
+// Close releases the textures and shaders.
+func (s *Screen) Close() {
+	s.post.Set("none")
+	s.post.resize(0, 0)
+	if s.target.ID != 0 {
+		rl.UnloadRenderTexture(s.target)
+	}
+}
+
+// Resize sets the size of the arcade screen in tiles and fits the window
+// to it.
+func (s *Screen) Resize(w, h int) {
+	width, height := int32(w*Size), int32(h*Size)
+	if width == s.width && height == s.height {
+		return
+	}
+	if s.target.ID != 0 {
+		rl.UnloadRenderTexture(s.target)
+	}
+	s.target = rl.LoadRenderTexture(width, height)
+	s.width, s.height = width, height
+	s.fitWindow()
+}
+
+// SetScale sets the window size in multiples of the arcade screen, 0 for
+// the largest that fits the monitor.
+func (s *Screen) SetScale(scale int) {
+	s.scale = min(max(scale, 0), MaxScale)
+	s.fitWindow()
+}
+
+// NextScale cycles through the window sizes that fit the monitor.
+func (s *Screen) NextScale() {
+	scale := s.windowScale() + 1
+	if scale > s.fitScale() {
+		scale = 1
+	}
+	s.SetScale(scale)
+}
+
+func (s *Screen) SetRotated(rotated bool) {
+	s.rotated = rotated
+	s.fitWindow()
+}
+
+func (s *Screen) Fullscreen() bool {
+	return rl.IsWindowState(rl.FlagBorderlessWindowedMode)
+}
+
+// ToggleFullscreen switches between a window and a borderless window
+// covering the monitor; the picture keeps its whole number scale either way.
+func (s *Screen) ToggleFullscreen() {
+	rl.ToggleBorderlessWindowed()
+	s.fitWindow()
+}
+
+// viewSize is the size of the picture in arcade pixels, turned if rotated.
+func (s *Screen) viewSize() (int32, int32) {
+	if s.rotated {
+		return s.height, s.width
+	}
+	return s.width, s.height
+}
+
+// fitScale is the largest scale whose window fits on the monitor.
+func (s *Screen) fitScale() int {
+	w, h := s.viewSize()
+	if w == 0 || h == 0 {
+		return 1
+	}
+	m := rl.GetCurrentMonitor()
+	fit := min((rl.GetMonitorWidth(m)-MonitorMargin)/int(w), (rl.GetMonitorHeight(m)-MonitorMargin)/int(h))
+	return min(max(fit, 1), MaxScale)
+}
+
+func (s *Screen) windowScale() int {
+	if s.scale == 0 {
+		return s.fitScale()
+	}
+	return s.scale
+}
+
+func (s *Screen) fitWindow() {
+	if !rl.IsWindowReady() || s.Fullscreen() || s.width == 0 {
+		return
+	}
+	w, h := s.viewSize()
+	scale := int32(s.windowScale())
+	rl.SetWindowSize(int(w*scale), int(h*scale))
+}
+
+// displayScale is the largest whole number scale that fits the window.
+func (s *Screen) displayScale() float32 {
+	w, h := s.viewSize()
+	return float32(max(1, min(int32(rl.GetScreenWidth())/w, int32(rl.GetScreenHeight())/h)))
+}
+
+func (s *Screen) rotation() float32 {
+	if s.rotated {
+		return 90
+	}
+	return 0
+}
+
+// Begin starts the game's pass, drawing in screen units to the texture.
+func (s *Screen) Begin() {
+	s.window = false
+	rl.BeginTextureMode(s.target)
+	rl.ClearBackground(rl.Black)
+	rl.BeginMode2D(s.Camera(screenView))
+}
+
+// Present draws the game's pass to the window and starts the tools' pass,
+// drawing in screen units over the picture at the window's resolution.
+func (s *Screen) Present() {
+	rl.EndMode2D()
+	rl.EndTextureMode()
+
+	scale := s.displayScale()
+	w, h := float32(s.width)*scale, float32(s.height)*scale
+	picture := s.target.Texture
+	src := rl.NewRectangle(0, 0, float32(s.width), -float32(s.height)) // render textures are upside down
+	if s.post.Active() {
+		picture = s.post.Apply(picture, scale).Texture
+		src = rl.NewRectangle(0, 0, w, -h)
+	}
+
+	rl.ClearBackground(rl.Black)
+	dst := rl.NewRectangle(float32(rl.GetScreenWidth())/2, float32(rl.GetScreenHeight())/2, w, h)
+	rl.DrawTexturePro(picture, src, dst, rl.Vector2{X: w / 2, Y: h / 2}, s.rotation(), rl.White)
+
+	s.window = true
+	rl.BeginMode2D(s.Camera(screenView))
+}
+
+// End ends the tools' pass.
+func (s *Screen) End() {
+	rl.EndMode2D()
+}
+
+// BeginView switches to a camera, such as the maze's, within a pass.
+func (s *Screen) BeginView(view rl.Camera2D) {
+	rl.EndMode2D()
+	rl.BeginMode2D(s.Camera(view))
+}
+
+// EndView switches back to plain screen units.
+func (s *Screen) EndView() {
+	s.BeginView(screenView)
+}
+
+// Camera returns the camera that draws what view puts in screen units to
+// the running pass: shrunk to arcade pixels for the texture, or scaled,
+// centered and turned like the picture for the window.
+func (s *Screen) Camera(view rl.Camera2D) rl.Camera2D {
+	if !s.window {
+		return rl.Camera2D{
+			Target: view.Target,
+			Offset: rl.Vector2Scale(view.Offset, 1.0/Zoom),
+			Zoom:   view.Zoom / Zoom,
+		}
+	}
+	return s.windowCamera(view)
+}
+
+func (s *Screen) windowCamera(view rl.Camera2D) rl.Camera2D {
+	center := rl.Vector2{X: float32(s.width*Zoom) / 2, Y: float32(s.height*Zoom) / 2}
+	return rl.Camera2D{
+		Target:   rl.Vector2Subtract(view.Target, rl.Vector2Scale(rl.Vector2Subtract(view.Offset, center), 1/view.Zoom)),
+		Offset:   rl.Vector2{X: float32(rl.GetScreenWidth()) / 2, Y: float32(rl.GetScreenHeight()) / 2},
+		Rotation: s.rotation(),
+		Zoom:     view.Zoom * s.displayScale() / Zoom,
+	}
+}
+
+// mouseTile returns the maze tile under the mouse.
+func (g *Game) mouseTile() (Vec2i, bool) {
+	mouse := rl.GetScreenToWorld2D(rl.GetMousePosition(), g.screen.windowCamera(g.camera2))
+	if mouse.X < 0 || mouse.Y < 0 {
+		return Vec2i{}, false
+	}
+	t := Vec2i{X: int(mouse.X) / Pixel, Y: int(mouse.Y) / Pixel}
+	return t, g.maze.Contains(t)
+}
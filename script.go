@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+const (
+	ScriptExt        = ".star"
+	ScriptMaxSteps   = 100_000              // starlark steps per call
+	ScriptMaxTime    = 2 * time.Millisecond // wall clock per call
+	ScriptReloadTime = time.Second          // how often to check for edits
+)
+
+// ScriptBehavior is a Behavior whose Chase, Scatter and ExitHouse logic is
+// written in a Starlark file, so ghost personalities can be tuned without
+// recompiling. A script defines any of
+//
+//	like = "clyde"        # borrow color, sprite and start from a built-in
+//	def chase(game):      # return the (x, y) target tile
+//	def scatter(game):    # return the (x, y) target tile
+//	def exit_house(game): # return True to leave the ghost house
+//
+// Anything the script leaves out falls back to the built-in behavior named
+// by like (Blinky by default), working from the script ghost's own tile.
+// The ghost still plays its own role, so a script like Blinky is never the
+// Blinky Inky pivots around. The game argument is a frozen, read-only view
+// of the player, the ghosts and the maze; see view for the fields.
+//
+// Scripts are sandboxed: there is no load, no file or network access, and
+// each call is cut off after ScriptMaxSteps steps or ScriptMaxTime. A failing
+// call logs the error and falls back to the built-in behavior for that frame.
+// The file is reloaded when it changes on disk.
+type ScriptBehavior struct {
+	Behavior // the built-in fallback
+
+	file      string
+	modTime   time.Time
+	checkedAt time.Time
+	globals   starlark.StringDict
+	lastErr   string
+}
+
+func NewScriptBehavior(file string) (*ScriptBehavior, error) {
+	s := &ScriptBehavior{file: file, Behavior: Blinky{}}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *ScriptBehavior) Id() GhostId {
+	return ScriptId
+}
+
+func (s *ScriptBehavior) load() error {
+	info, err := os.Stat(s.file)
+	if err != nil {
+		return err
+	}
+
+	thread := s.newThread()
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, s.file, nil, nil)
+	if err != nil {
+		return fmt.Errorf("ghost script %s: %w", s.file, err)
+	}
+
+	base := Behavior(Blinky{})
+	if like, ok := globals["like"]; ok {
+		name, ok := starlark.AsString(like)
+		if !ok {
+			return fmt.Errorf("ghost script %s: like must be a string", s.file)
+		}
+		newBehavior, ok := behaviors[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("ghost script %s: unknown ghost %q", s.file, name)
+		}
+		base = newBehavior()
+	}
+
+	globals.Freeze()
+	s.Behavior = base
+	s.globals = globals
+	s.modTime = info.ModTime()
+	aiLog.Info("loaded ghost script", "file", s.file)
+	return nil
+}
+
+// reload picks up edits to the script, keeping the old version if the new
+// one fails to load.
+func (s *ScriptBehavior) reload() {
+	now := time.Now()
+	if now.Sub(s.checkedAt) < ScriptReloadTime {
+		return
+	}
+	s.checkedAt = now
+
+	info, err := os.Stat(s.file)
+	if err != nil || !info.ModTime().After(s.modTime) {
+		return
+	}
+
+	if err := s.load(); err != nil {
+		s.modTime = info.ModTime() // don't retry until it changes again
+		aiLog.Warn("reloading ghost script", "err", err)
+	}
+}
+
+func (s *ScriptBehavior) newThread() *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  s.file,
+		Print: func(_ *starlark.Thread, msg string) { aiLog.Info(msg, "file", s.file) },
+	}
+	thread.SetMaxExecutionSteps(ScriptMaxSteps)
+	return thread
+}
+
+// call runs the named script function with a fresh view of the game from
+// the ghost self. ok is false when the function is missing or fails.
+func (s *ScriptBehavior) call(game *Game, self *Ghost, name string) (starlark.Value, bool) {
+	s.reload()
+
+	fn, found := s.globals[name]
+	if !found {
+		return nil, false
+	}
+
+	thread := s.newThread()
+	timer := time.AfterFunc(ScriptMaxTime, func() { thread.Cancel("time limit exceeded") })
+	defer timer.Stop()
+
+	result, err := starlark.Call(thread, fn, starlark.Tuple{s.view(game, self)}, nil)
+	if err != nil {
+		s.fail(fmt.Sprintf("ghost script %s: %s: %v", s.file, name, err))
+		return nil, false
+	}
+
+	return result, true
+}
+
+// fail logs err once, not every frame it keeps happening.
+func (s *ScriptBehavior) fail(err string) {
+	if err != s.lastErr {
+		aiLog.Warn(err)
+		s.lastErr = err
+	}
+}
+
+func (s *ScriptBehavior) target(game *Game, self *Ghost, name string, fallback func(*Game, *Ghost) Vec2i) Vec2i {
+	v, ok := s.call(game, self, name)
+	if !ok {
+		return fallback(game, self)
+	}
+
+	tile, err := toTile(v)
+	if err != nil {
+		s.fail(fmt.Sprintf("ghost script %s: %s returned %s: %v", s.file, name, v, err))
+		return fallback(game, self)
+	}
+
+	return tile
+}
+
+func (s *ScriptBehavior) Chase(game *Game, self *Ghost) Vec2i {
+	return s.target(game, self, "chase", s.Behavior.Chase)
+}
+
+func (s *ScriptBehavior) Scatter(game *Game, self *Ghost) Vec2i {
+	return s.target(game, self, "scatter", s.Behavior.Scatter)
+}
+
+func (s *ScriptBehavior) ExitHouse(game *Game, self *Ghost) bool {
+	v, ok := s.call(game, self, "exit_house")
+	if !ok {
+		return s.Behavior.ExitHouse(game, self)
+	}
+	return bool(v.Truth())
+}
+
+// view builds the read-only game argument passed to scripts:
+//
+//	game.level, game.time, game.dots_eaten
+//	game.player.tile, game.player.dir
+//	game.ghosts[i].name, .tile, .dir, .state
+//	game.self                      this ghost, or None
+//	game.width, game.height
+//	game.is_wall(x, y)
+//	game.is_intersection(x, y)
+//	game.distance(x1, y1, x2, y2)  shortest path length, -1 if unreachable
+func (s *ScriptBehavior) view(game *Game, ghost *Ghost) starlark.Value {
+	ghosts := make([]starlark.Value, 0, len(game.ghosts))
+	var self starlark.Value = starlark.None
+	for _, g := range game.ghosts {
+		v := starlarkstruct.FromStringDict(starlark.String("ghost"), starlark.StringDict{
+			"name":  starlark.String(strings.ToLower(g.name)),
+			"tile":  tileValue(g.tile),
+			"dir":   dirValue(g.dir),
+			"state": starlark.String(g.state.String()),
+		})
+		ghosts = append(ghosts, v)
+		if g == ghost {
+			self = v
+		}
+	}
+
+	p := game.player
+	player := starlarkstruct.FromStringDict(starlark.String("player"), starlark.StringDict{
+		"tile": tileValue(p.tile),
+		"dir":  dirValue(p.dir),
+	})
+
+	maze := game.maze
+	graph := game.graph
+	v := starlarkstruct.FromStringDict(starlark.String("game"), starlark.StringDict{
+		"level":      starlark.MakeInt(game.level),
+		"time":       starlark.Float(game.levelTime),
+		"dots_eaten": starlark.MakeInt(game.dotsEaten),
+		"width":      starlark.MakeInt(len(maze[0])),
+		"height":     starlark.MakeInt(len(maze)),
+		"player":     player,
+		"ghosts":     starlark.NewList(ghosts),
+		"self":       self,
+		"is_wall": starlark.NewBuiltin("is_wall", func(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var x, y int
+			if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &x, &y); err != nil {
+				return nil, err
+			}
+			return starlark.Bool(!maze.IsValidMove(Vec2i{X: x, Y: y})), nil
+		}),
+		"is_intersection": starlark.NewBuiltin("is_intersection", func(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var x, y int
+			if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &x, &y); err != nil {
+				return nil, err
+			}
+			t := Vec2i{X: x, Y: y}
+			return starlark.Bool(maze.IsValidMove(t) && graph.IsIntersection(t)), nil
+		}),
+		"distance": starlark.NewBuiltin("distance", func(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var x1, y1, x2, y2 int
+			if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 4, &x1, &y1, &x2, &y2); err != nil {
+				return nil, err
+			}
+			return starlark.MakeInt(graph.Distance(Vec2i{X: x1, Y: y1}, Vec2i{X: x2, Y: y2})), nil
+		}),
+	})
+	v.Freeze()
+	return v
+}
+
+func tileValue(t Vec2i) starlark.Tuple {
+	return starlark.Tuple{starlark.MakeInt(t.X), starlark.MakeInt(t.Y)}
+}
+
+func dirValue(d Direction) starlark.Value {
+	if d == None {
+		return starlark.None
+	}
+	return starlark.String(strings.ToLower(d.String()))
+}
+
+func toTile(v starlark.Value) (Vec2i, error) {
+	seq, ok := v.(starlark.Indexable)
+	if !ok || seq.Len() != 2 {
+		return Vec2i{}, fmt.Errorf("want an (x, y) tuple")
+	}
+
+	x, err := starlark.AsInt32(seq.Index(0))
+	if err != nil {
+		return Vec2i{}, err
+	}
+	y, err := starlark.AsInt32(seq.Index(1))
+	if err != nil {
+		return Vec2i{}, err
+	}
+
+	return Vec2i{X: x, Y: y}, nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// setting is a line of the settings menu. Change steps the value back or
+// forward and applies it right away; settings without one open something
+// on enter instead.
+type setting struct {
+	name   string
+	value  func(g *Game) string
+	change func(g *Game, step int)
+}
+
+var settings = []setting{
+	{name: "difficulty",
+		value: func(g *Game) string { return g.rules.Preset.String() },
+		change: func(g *Game, step int) {
+			g.rules.Preset = Preset(wrap(int(g.rules.Preset)+step, int(PresetHard)+1))
+			g.config.Difficulty = g.rules.Preset.String()
+		}},
+	{name: "lives",
+		value: func(g *Game) string { return fmt.Sprintf("%d", g.rules.Lives) },
+		change: func(g *Game, step int) {
+			g.rules.Lives = wrap(g.rules.Lives-1+step, MaxLives) + 1
+			g.config.Lives = g.rules.Lives
+		}},
+	{name: "bonus life",
+		value: func(g *Game) string {
+			if g.rules.BonusLife == 0 {
+				return "none"
+			}
+			return fmt.Sprintf("%d", g.rules.BonusLife)
+		},
+		change: func(g *Game, step int) {
+			i := slices.Index(BonusLives, g.rules.BonusLife)
+			g.rules.BonusLife = BonusLives[wrap(i+step, len(BonusLives))]
+			g.config.BonusLife = g.rules.BonusLife
+		}},
+	{name: "chase bug",
+		value: func(g *Game) string { return onOff(g.rules.ChaseBug) },
+		change: func(g *Game, _ int) {
+			g.rules.ChaseBug = !g.rules.ChaseBug
+			g.config.ChaseBug = g.rules.ChaseBug
+		}},
+	{name: "scale",
+		value: func(g *Game) string {
+			if g.screen.scale == 0 {
+				return "fit"
+			}
+			return fmt.Sprintf("%dx", g.screen.scale)
+		},
+		change: func(g *Game, step int) {
+			g.screen.SetScale(wrap(g.screen.scale+step, MaxScale+1))
+			g.config.Scale = g.screen.scale
+		}},
+	{name: "fullscreen",
+		value: func(g *Game) string { return onOff(g.screen.Fullscreen()) },
+		change: func(g *Game, _ int) {
+			g.config.Fullscreen = !g.screen.Fullscreen()
+			g.screen.ToggleFullscreen()
+		}},
+	{name: "rotate",
+		value: func(g *Game) string { return onOff(g.screen.rotated) },
+		change: func(g *Game, _ int) {
+			g.screen.SetRotated(!g.screen.rotated)
+			g.config.Rotate = g.screen.rotated
+		}},
+	{name: "effects",
+		value: func(g *Game) string {
+			if i := effectPreset(g); i >= 0 {
+				return EffectPresets[i].Name
+			}
+			return "custom"
+		},
+		change: func(g *Game, step int) {
+			preset := EffectPresets[wrap(effectPreset(g)+step, len(EffectPresets))]
+			if err := g.screen.post.Set(preset.Effects); err != nil {
+				gameLog.Warn("effects setting", "err", err)
+				return
+			}
+			g.config.Effects = preset.Effects
+		}},
+	{name: "volume",
+		value: func(g *Game) string { return fmt.Sprintf("%d%%", int(g.audio.Volume()*100+0.5)) },
+		change: func(g *Game, step int) {
+			volume := min(max(g.audio.Volume()+float32(step)*VolumeStep, 0), 1)
+			g.audio.SetVolume(volume)
+			g.config.Volume = volume
+		}},
+	{name: "language",
+		value: func(g *Game) string { return g.catalog.Language },
+		change: func(g *Game, step int) {
+			langs := Languages()
+			lang := langs[wrap(slices.Index(langs, g.catalog.Language)+step, len(langs))]
+			if err := g.setLanguage(lang); err != nil {
+				gameLog.Warn("language setting", "err", err)
+				return
+			}
+			g.config.Language = lang
+		}},
+	{name: "controls"},
+}
+
+const VolumeStep = 0.1
+
+// SettingsMenu changes the settings kept in the settings file. Up and down
+// pick a setting, left and right change it, enter (or the start action)
+// steps it forward or opens the controls, and the settings action closes
+// the menu. Changes are saved as they are made; rule changes that only
+// matter at the start of a game, like the lives, wait for the next one.
+type SettingsMenu struct {
+	selected int
+}
+
+func NewSettingsMenu() *SettingsMenu {
+	return &SettingsMenu{}
+}
+
+func (m *SettingsMenu) Update(g *Game) {
+	in := g.input
+	s := settings[m.selected]
+
+	step := 0
+	switch {
+	case in.Pressed(ActionSettings):
+		g.settings = nil
+	case in.Pressed(ActionUp):
+		m.selected = wrap(m.selected-1, len(settings))
+	case in.Pressed(ActionDown):
+		m.selected = wrap(m.selected+1, len(settings))
+	case in.Pressed(ActionLeft):
+		step = -1
+	case in.Pressed(ActionRight):
+		step = 1
+	case rl.IsKeyPressed(rl.KeyEnter) || in.Pressed(ActionStart):
+		if s.change == nil {
+			g.rebind = NewRebindScreen()
+			return
+		}
+		step = 1
+	}
+
+	if step == 0 || s.change == nil {
+		return
+	}
+	s.change(g, step)
+	if err := g.config.Save(); err != nil {
+		gameLog.Error("saving settings", "err", err)
+	}
+}
+
+func (m *SettingsMenu) Draw(g *Game) {
+	w, h := g.screenSize()
+	rl.DrawRectangle(0, 0, int32(w*Pixel), int32(h*Pixel), rl.ColorAlpha(rl.Black, 0.9))
+
+	g.drawTextAligned(g.tr("SETTINGS"), float32(w*Pixel/2), 3*Pixel, AlignCenter, rl.Yellow)
+	for i, s := range settings {
+		color := rl.White
+		if i == m.selected {
+			color = rl.Yellow
+		}
+
+		y := 6 + i*2
+		g.drawText(g.tr(s.name), 2, y, 0, color)
+		if s.value != nil {
+			g.drawText(g.tr(s.value(g)), 16, y, 0, color)
+		}
+	}
+
+	hint := fmt.Sprintf(g.tr("%s CLOSE"), bindingName(g.input.Binding(ActionSettings)))
+	g.drawTextAligned(hint, float32(w*Pixel/2), float32((h-2)*Pixel), AlignCenter, rl.Gray)
+}
+
+// effectPreset finds the preset the effects chain is, or -1.
+func effectPreset(g *Game) int {
+	chain := g.screen.post.String()
+	return slices.IndexFunc(EffectPresets, func(p EffectPreset) bool { return p.Effects == chain })
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// wrap keeps i in 0 to n-1, going around at either end.
+func wrap(i, n int) int {
+	return (i%n + n) % n
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Voice 0 plays music, voice 1 one shot effects and voice 2 the background
+// sirens, like the arcade.
+const (
+	musicVoice = 0
+	fxVoice    = 1
+	sirenVoice = 2
+)
+
+// wsgEffects are the WSG programs for every sound.
+var wsgEffects = [NumSounds]wsgEffect{
+	SoundIntro: voices(map[int][]wsgStep{
+		musicVoice: tune(7, 12, 8,
+			523, 659, 784, 659, 523, 659, 784, 0,
+			587, 698, 880, 698, 587, 698, 880, 0,
+			659, 784, 988, 784, 1047, 988, 784, 659,
+			587, 0, 523, 0, 523, 0, 0, 0),
+		fxVoice: tune(3, 10, 16,
+			131, 196, 131, 196, 147, 220, 147, 220,
+			165, 247, 196, 165, 147, 131, 131, 0),
+	}),
+	SoundMunchA: voices(map[int][]wsgStep{
+		fxVoice: {{Frames: 4, Wave: 1, Freq: hz(700), FreqStep: -hz(110), Volume: 12}},
+	}),
+	SoundMunchB: voices(map[int][]wsgStep{
+		fxVoice: {{Frames: 4, Wave: 1, Freq: hz(260), FreqStep: hz(110), Volume: 12}},
+	}),
+	SoundSiren: voices(map[int][]wsgStep{
+		sirenVoice: {
+			{Frames: 12, Wave: 6, Freq: hz(420), FreqStep: hz(32), Volume: 8},
+			{Frames: 12, Wave: 6, Freq: hz(804), FreqStep: -hz(32), Volume: 8},
+		},
+	}),
+	SoundPowerSiren: voices(map[int][]wsgStep{
+		sirenVoice: repeat(4, wsgStep{Frames: 4, Wave: 2, Freq: hz(180), FreqStep: hz(45), Volume: 6}),
+	}),
+	SoundGhostEaten: voices(map[int][]wsgStep{
+		fxVoice: {{Frames: 30, Wave: 2, Freq: hz(200), FreqStep: hz(55), Volume: 10, VolStep: 0}},
+	}),
+	SoundEyes: voices(map[int][]wsgStep{
+		sirenVoice: repeat(2, wsgStep{Frames: 5, Wave: 3, Freq: hz(1200), FreqStep: hz(240), Volume: 9}),
+	}),
+	SoundFruitBounce: voices(map[int][]wsgStep{
+		fxVoice: {
+			{Frames: 3, Wave: 3, Freq: hz(300), FreqStep: hz(65), Volume: 11},
+			{Frames: 3, Wave: 3, Freq: hz(500), FreqStep: -hz(65), Volume: 11},
+		},
+	}),
+	SoundDeath: voices(map[int][]wsgStep{
+		fxVoice: deathSteps(),
+	}),
+	SoundExtraLife: voices(map[int][]wsgStep{
+		fxVoice: repeat(4,
+			wsgStep{Frames: 5, Wave: 0, Freq: hz(1568), Volume: 12},
+			wsgStep{Frames: 3, Wave: 0, Freq: hz(1568), Volume: 0},
+		),
+	}),
+}
+
+func voices(programs map[int][]wsgStep) wsgEffect {
+	var e wsgEffect
+	for voice, steps := range programs {
+		e[voice] = steps
+	}
+	return e
+}
+
+// tune turns notes in Hz (0 for a rest), each lasting frames, into steps.
+// Every note decays a little so repeated notes are heard separately.
+func tune(wave, volume, frames int, notes ...float64) []wsgStep {
+	steps := make([]wsgStep, 0, len(notes))
+	for _, n := range notes {
+		s := wsgStep{Frames: frames, Wave: wave, Freq: hz(n), Volume: volume, VolStep: -1}
+		if n == 0 {
+			s.Volume = 0
+		}
+		steps = append(steps, s)
+	}
+	return steps
+}
+
+func repeat(n int, steps ...wsgStep) []wsgStep {
+	var result []wsgStep
+	for range n {
+		result = append(result, steps...)
+	}
+	return result
+}
+
+// deathSteps is a falling warble followed by two pops.
+func deathSteps() []wsgStep {
+	var steps []wsgStep
+	for i := range 8 {
+		f := 900 - float64(i)*90
+		steps = append(steps, wsgStep{Frames: 7, Wave: 3, Freq: hz(f), FreqStep: -hz(22), Volume: 12})
+	}
+	pop := wsgStep{Frames: 6, Wave: 2, Freq: hz(120), FreqStep: hz(60), Volume: 12, VolStep: -2}
+	gap := wsgStep{Frames: 4, Volume: 0}
+	return append(steps, pop, gap, pop)
+}
+
+// ExportSounds renders every sound effect to a WAV file in dir, so effects
+// can be listened to and compared without running the game.
+func ExportSounds(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for i := range NumSounds {
+		id := SoundId(i)
+		file := filepath.Join(dir, id.String()+".wav")
+		if err := WriteWAV(file, RenderEffect(id, SoundSampleRate), SoundSampleRate); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s\n", file)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+#version 330
+
+// Smears colors to the right like a composite video signal.
+
+in vec2 fragTexCoord;
+in vec4 fragColor;
+
+out vec4 finalColor;
+
+uniform sampler2D texture0;
+uniform vec2 sourceSize; // arcade pixels
+uniform float amount;    // 0-1
+uniform float width;     // smear in arcade pixels
+
+void main()
+{
+    vec4 color = texture(texture0, fragTexCoord) * fragColor;
+    vec2 step = vec2(width / 2.0 / sourceSize.x, 0.0);
+    vec3 left = (texture(texture0, fragTexCoord - step).rgb + texture(texture0, fragTexCoord - 2.0 * step).rgb) / 2.0;
+    finalColor = vec4(mix(color.rgb, max(color.rgb, left), amount), color.a);
+}
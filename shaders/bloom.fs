@@ -0,0 +1,33 @@
+#version 330
+
+// Adds a glow around bright colors.
+
+in vec2 fragTexCoord;
+in vec4 fragColor;
+
+out vec4 finalColor;
+
+uniform sampler2D texture0;
+uniform vec2 sourceSize;  // arcade pixels
+uniform float amount;     // glow brightness
+uniform float threshold;  // how bright a color must be to glow, 0-1
+uniform float radius;     // glow size in arcade pixels
+
+void main()
+{
+    vec4 color = texture(texture0, fragTexCoord) * fragColor;
+    vec2 step = radius / 2.0 / sourceSize;
+    vec3 glow = vec3(0.0);
+    float total = 0.0;
+    for (int y = -2; y <= 2; y++)
+    {
+        for (int x = -2; x <= 2; x++)
+        {
+            float weight = exp(-float(x * x + y * y) / 4.0);
+            vec3 c = texture(texture0, fragTexCoord + vec2(x, y) * step).rgb;
+            glow += max(c - threshold, 0.0) * weight;
+            total += weight;
+        }
+    }
+    finalColor = vec4(color.rgb + amount * glow / total, color.a);
+}
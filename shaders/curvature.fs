@@ -0,0 +1,27 @@
+#version 330
+
+// Bends the picture like the glass of a tube and darkens its corners.
+
+in vec2 fragTexCoord;
+in vec4 fragColor;
+
+out vec4 finalColor;
+
+uniform sampler2D texture0;
+uniform float amount;   // bend, 0 is flat
+uniform float vignette; // corner darkening, 0-1
+
+void main()
+{
+    vec2 centered = fragTexCoord - 0.5;
+    vec2 uv = 0.5 + centered * (1.0 + amount * dot(centered, centered));
+    if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0)
+    {
+        finalColor = vec4(0.0, 0.0, 0.0, 1.0);
+        return;
+    }
+
+    vec4 color = texture(texture0, uv) * fragColor;
+    float edge = pow(16.0 * uv.x * uv.y * (1.0 - uv.x) * (1.0 - uv.y), 0.25);
+    finalColor = vec4(color.rgb * mix(1.0, edge, vignette), color.a);
+}
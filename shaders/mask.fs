@@ -0,0 +1,21 @@
+#version 330
+
+// Stripes of red, green and blue phosphor like an aperture grille.
+
+in vec2 fragTexCoord;
+in vec4 fragColor;
+
+out vec4 finalColor;
+
+uniform sampler2D texture0;
+uniform float strength; // 0-1
+uniform float size;     // window pixels per stripe
+
+void main()
+{
+    vec4 color = texture(texture0, fragTexCoord) * fragColor;
+    int stripe = int(mod(floor(gl_FragCoord.x / max(size, 1.0)), 3.0));
+    vec3 phosphor = vec3(0.5);
+    phosphor[stripe] = 1.5;
+    finalColor = vec4(color.rgb * mix(vec3(1.0), phosphor, strength), color.a);
+}
@@ -0,0 +1,21 @@
+#version 330
+
+// Darkens the gaps between the arcade's pixel rows.
+
+in vec2 fragTexCoord;
+in vec4 fragColor;
+
+out vec4 finalColor;
+
+uniform sampler2D texture0;
+uniform vec2 sourceSize; // arcade pixels
+uniform float strength;  // how dark the gaps get, 0-1
+uniform float sharpness; // higher is thinner lines
+
+void main()
+{
+    vec4 color = texture(texture0, fragTexCoord) * fragColor;
+    float row = fract(fragTexCoord.y * sourceSize.y);
+    float gap = pow(abs(row * 2.0 - 1.0), sharpness);
+    finalColor = vec4(color.rgb * (1.0 - strength * gap), color.a);
+}
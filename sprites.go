@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// KeyThreshold is how far from black, in any channel, a background pixel
+// may be.
+const KeyThreshold = 12
+
+// LoadSpriteSheet loads the artwork as RGBA with a transparent background,
+// so sprites draw over the maze without a shader. The image is kept for
+// reading the built-in boards; keying only changes alpha, so it reads the
+// same as the original.
+func LoadSpriteSheet(file string) (rl.Texture2D, *rl.Image) {
+	image := rl.LoadImage(file)
+	rl.ImageFormat(image, rl.UncompressedR8g8b8a8)
+	colorKey(image)
+	return rl.LoadTextureFromImage(image), image
+}
+
+// openAreas are the parts of the spritesheet where every near-black pixel
+// is background, even where a sprite encloses it: the fruits, with gaps
+// between their parts, and the rows of point sprites, with the holes in
+// their digits. They have no dark details to keep.
+var openAreas = []rect{
+	{Min: Vec2i{X: 504, Y: 0}, Max: Vec2i{X: 504 + (int(Banana)+1)*16 - 1, Y: 15}}, // see FruitKind.Sprite
+	{Min: Vec2i{X: 456, Y: 128}, Max: Vec2i{X: math.MaxInt, Y: 159}},
+}
+
+// colorKey makes the black background transparent: every near-black pixel
+// connected to the edge of the image through other near-black pixels, and
+// every one in openAreas. Elsewhere dark areas a sprite encloses stay
+// opaque, which keeps the ghosts' pupils but also any enclosed background
+// outside openAreas, so a skin that moves its sprites must keep them within
+// the same areas. Images that already have transparency, e.g. a custom skin
+// drawn with true black, are left alone.
+func colorKey(image *rl.Image) {
+	w, h := int(image.Width), int(image.Height)
+	colors := rl.LoadImageColors(image)
+	defer rl.UnloadImageColors(colors)
+	for _, c := range colors {
+		if c.A < 255 {
+			return
+		}
+	}
+
+	background := func(x, y int) bool {
+		c := colors[y*w+x]
+		return c.R <= KeyThreshold && c.G <= KeyThreshold && c.B <= KeyThreshold
+	}
+
+	seen := make([]bool, w*h)
+	var queue []Vec2i
+	visit := func(x, y int) {
+		if x >= 0 && x < w && y >= 0 && y < h && !seen[y*w+x] && background(x, y) {
+			seen[y*w+x] = true
+			queue = append(queue, Vec2i{X: x, Y: y})
+		}
+	}
+	for x := range w {
+		visit(x, 0)
+		visit(x, h-1)
+	}
+	for y := range h {
+		visit(0, y)
+		visit(w-1, y)
+	}
+
+	for _, area := range openAreas {
+		for y := area.Min.Y; y <= min(area.Max.Y, h-1); y++ {
+			for x := area.Min.X; x <= min(area.Max.X, w-1); x++ {
+				visit(x, y)
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		p := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		c := colors[p.Y*w+p.X]
+		c.A = 0
+		rl.ImageDrawPixel(image, int32(p.X), int32(p.Y), c)
+
+		visit(p.X-1, p.Y)
+		visit(p.X+1, p.Y)
+		visit(p.X, p.Y-1)
+		visit(p.X, p.Y+1)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Stats counts the events of a game, e.g. to compare bot runs after a rule
+// change.
+type Stats struct {
+	counts map[string]int
+	ticks  int
+}
+
+func NewStats() *Stats {
+	return &Stats{counts: make(map[string]int)}
+}
+
+func (s *Stats) HandleEvent(_ *Game, tick int, e Event) {
+	s.counts[EventName(e)]++
+	s.ticks = tick
+}
+
+func (s *Stats) String() string {
+	names := make([]string, 0, len(s.counts))
+	for name := range s.counts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "ticks: %d\n", s.ticks)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "%s: %d\n", name, s.counts[name])
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Sue (Orange, Ms. Pac-Man)
+//    Chase:
+//       Ms. Pac-Man's replacement for Clyde. Like Clyde she heads straight
+//       for Player when more than eight tiles away and gives up and
+//       returns to her corner when closer.
+//    Scatter:
+//       Moves to the bottom-left corner of the maze.
+
+func (b Sue) Id() GhostId {
+	return SueId
+}
+
+func (b Sue) Color() rl.Color {
+	return rl.Gold
+}
+
+func (b Sue) StartingTile(game *Game) Vec2i {
+	return Vec2i{X: 16, Y: 11}
+}
+
+func (b Sue) StartingDir(game *Game) Direction {
+	return Right
+}
+
+func (b Sue) Sprite() Vec2i {
+	return Vec2i{X: 520, Y: 112}
+}
+
+func (b Sue) Chase(game *Game, self *Ghost) Vec2i {
+	if game.player.tile.Distance(self.tile) > 8 {
+		return game.player.tile
+	}
+
+	return b.Scatter(game, self)
+}
+
+func (b Sue) Scatter(game *Game, _ *Ghost) Vec2i {
+	return Vec2i{X: 1, Y: 29} // depends on board
+}
+
+func (b Sue) ExitHouse(game *Game, _ *Ghost) bool {
+	return game.dotsEaten > 60 && game.levelTime > 15
+}
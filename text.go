@@ -1,56 +1,33 @@
 package main
 
 import (
-	"strings"
-
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-func (g *Game) drawText(text string, x, y, pixelOffset int, color rl.Color) {
-
-	str := []byte(strings.ToUpper(text))
-	var fx, fy int
-
-	for i, c := range str {
-		ch := int(c)
-		if ch >= 48 && ch <= 57 {
-			// 0 - 9
-			fx = ch - 48
-			fy = 2
-		} else if ch >= 65 && ch <= 79 {
-			// A - O
-			fx = ch - 65
-			fy = 0
-		} else if ch >= 80 && ch <= 90 {
-			// P - Z
-			fx = ch - 80
-			fy = 1
-		} else if ch == 33 {
-			// "!"
-			fx = 11
-			fy = 1
-		} else if ch == 47 {
-			// "/"
-			fx = 10
-			fy = 2
-		} else if ch == 45 {
-			// "-"
-			fx = 11
-			fy = 2
-		} else if ch == 34 {
-			// '"'
-			fx = 12
-			fy = 2
-		} else {
-			// space or unmapped char
-			continue
-		}
+// Align is which end of a text its position is.
+type Align int
 
-		// ! = 33
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
 
-		src := rl.NewRectangle(float32(fx*Size), float32(fy*Size), Size, Size)
-		dst := rl.NewRectangle(float32((x+i)*Pixel), float32(y*Pixel)+float32(pixelOffset), Pixel, Pixel)
-		rl.DrawTexturePro(g.font, src, dst, rl.Vector2{}, 0, color)
+// drawText draws text a tile high starting at a tile, moved down by
+// pixelOffset screen units.
+func (g *Game) drawText(text string, x, y, pixelOffset int, color rl.Color) {
+	pos := rl.Vector2{X: float32(x * Pixel), Y: float32(y*Pixel + pixelOffset)}
+	g.font.Draw(text, pos, Pixel, color)
+}
 
+// drawTextAligned draws text a tile high starting, centered or ending at x
+// in screen units.
+func (g *Game) drawTextAligned(text string, x, y float32, align Align, color rl.Color) {
+	switch align {
+	case AlignCenter:
+		x -= g.font.Measure(text, Pixel) / 2
+	case AlignRight:
+		x -= g.font.Measure(text, Pixel)
 	}
+	g.font.Draw(text, rl.Vector2{X: x, Y: y}, Pixel, color)
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// Tracer writes one JSON object per simulation tick with the position,
+// direction, target and state of every actor and the events of the tick,
+// for analysing the AI's decisions offline, e.g. with jq.
+type Tracer struct {
+	file   *os.File
+	w      *bufio.Writer
+	enc    *json.Encoder
+	events []string
+}
+
+type traceTick struct {
+	Tick   int          `json:"tick"`
+	Time   float64      `json:"time"`
+	Level  int          `json:"level"`
+	Dots   int          `json:"dots"`
+	Player traceActor   `json:"player"`
+	Ghosts []traceActor `json:"ghosts"`
+	Fruit  *traceActor  `json:"fruit,omitempty"`
+	Events []string     `json:"events,omitempty"`
+}
+
+type traceActor struct {
+	Name   string     `json:"name"`
+	Tile   [2]int     `json:"tile"`
+	Pixel  [2]float32 `json:"pixel"`
+	Dir    string     `json:"dir,omitempty"`
+	Target *[2]int    `json:"target,omitempty"`
+	State  string     `json:"state,omitempty"`
+}
+
+func NewTracer(file string) (*Tracer, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &Tracer{file: f, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+// HandleEvent collects the events of the tick for the next trace line.
+func (t *Tracer) HandleEvent(_ *Game, _ int, e Event) {
+	t.events = append(t.events, EventName(e)+": "+e.String())
+}
+
+// Trace writes the line for the tick that just ran.
+func (t *Tracer) Trace(g *Game) error {
+	line := traceTick{
+		Tick:   g.tick,
+		Time:   g.levelTime,
+		Level:  g.level,
+		Dots:   g.dotsEaten,
+		Player: traceEntity(&g.player.Entity),
+		Events: t.events,
+	}
+	for _, ghost := range g.ghosts {
+		actor := traceEntity(&ghost.Entity)
+		actor.Target = &[2]int{ghost.target.X, ghost.target.Y}
+		actor.State = ghost.state.String()
+		line.Ghosts = append(line.Ghosts, actor)
+	}
+	if g.fruit != nil {
+		fruit := traceEntity(&g.fruit.Entity)
+		line.Fruit = &fruit
+	}
+
+	t.events = t.events[:0]
+	return t.enc.Encode(line)
+}
+
+func (t *Tracer) Close() error {
+	if err := t.w.Flush(); err != nil {
+		t.file.Close()
+		return err
+	}
+	return t.file.Close()
+}
+
+func traceEntity(e *Entity) traceActor {
+	actor := traceActor{
+		Name:  e.name,
+		Tile:  [2]int{e.tile.X, e.tile.Y},
+		Pixel: [2]float32{e.pixel.X, e.pixel.Y},
+	}
+	if e.dir != None {
+		actor.Dir = e.dir.String()
+	}
+	return actor
+}
@@ -1,77 +1,170 @@
 package main
 
 import (
-	"fmt"
-
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 func (g *Game) Update() {
-	g.levelTime = rl.GetTime() - g.startTime
-	p := g.player
-	moved := false
-	if rl.IsKeyPressed(rl.KeyRight) {
-		moved = true
-		p.nextDir = Right
-		p.nextVel = Vec2i{X: 1}
+	g.update()
+
+	// events from the console and debug keys
+	g.bus.Flush(g)
+	g.audio.Update(g)
+	g.popups.Update(g)
+}
+
+// update handles the input of a rendered frame and runs as many simulation
+// ticks as the time scale asks for.
+func (g *Game) update() {
+	g.input.Update()
+	g.moved = false
+
+	if g.rebind != nil {
+		g.rebind.Update(g)
+		return
+	}
+
+	if g.input.Pressed(ActionRebind) {
+		g.rebind = NewRebindScreen()
+		return
+	}
+
+	if g.console.open {
+		g.console.Update(g)
+		return
+	}
+
+	if g.input.Pressed(ActionConsole) {
+		g.console.Open()
+		return
+	}
+
+	if g.settings != nil {
+		g.settings.Update(g)
+		return
+	}
+
+	if g.input.Pressed(ActionSettings) {
+		g.settings = NewSettingsMenu()
+		return
 	}
 
-	if rl.IsKeyPressed(rl.KeyLeft) {
-		moved = true
-		p.nextDir = Left
-		p.nextVel = Vec2i{X: -1}
+	if g.input.Pressed(ActionFullscreen) {
+		g.screen.ToggleFullscreen()
 	}
 
-	if rl.IsKeyPressed(rl.KeyUp) {
-		moved = true
-		p.nextDir = Up
-		p.nextVel = Vec2i{Y: -1}
+	if g.input.Pressed(ActionScale) {
+		g.screen.NextScale()
 	}
 
-	if rl.IsKeyPressed(rl.KeyDown) {
-		moved = true
-		p.nextDir = Down
-		p.nextVel = Vec2i{Y: 1}
+	if g.input.Pressed(ActionRotate) {
+		g.screen.SetRotated(!g.screen.rotated)
 	}
 
-	if rl.IsKeyPressed(rl.KeyD) {
+	if g.editor != nil {
+		g.editor.Update(g)
+		return
+	}
+
+	if g.input.Pressed(ActionEditor) {
+		g.editor = NewEditor(g)
+		return
+	}
+
+	if g.input.Pressed(ActionDebug) {
 		g.debug = !g.debug
 	}
 
-	if rl.IsKeyPressed(rl.KeyL) {
-		g.debugLayout = !g.debugLayout
+	g.overlays.Update(g)
+
+	if g.input.Pressed(ActionDebugLayout) {
+		g.overlays.Toggle("grid")
 	}
 
-	if rl.IsKeyPressed(rl.KeyN) {
-		g.boardNum = (g.boardNum + 1) % 6
+	if g.input.Pressed(ActionNextBoard) {
+		g.boardNum = (g.boardNum + 1) % len(g.boards)
 		g.mapBoard()
+		g.resetActors()
+	}
+
+	if g.input.Pressed(ActionCoin) {
+		g.insertCoin()
+	}
+
+	if g.input.Pressed(ActionStart) {
+		g.pressStart()
 	}
 
-	if rl.IsKeyPressed(rl.KeyP) || rl.IsKeyPressed(rl.KeySpace) {
-		moved = true
+	if g.input.Pressed(ActionPause) {
+		g.moved = true
 		g.paused = !g.paused
 	}
 
-	g.moved = moved
+	if g.input.Pressed(ActionSlower) {
+		g.setTimeScale(g.timeScale / 2)
+	}
+
+	if g.input.Pressed(ActionFaster) {
+		g.setTimeScale(g.timeScale * 2)
+	}
 
 	if g.debug {
-		if rl.IsKeyPressed(rl.KeyC) {
+		if g.input.Pressed(ActionChase) {
 			g.setGhostMode(Chase)
 		}
 
-		if rl.IsKeyPressed(rl.KeyS) {
+		if g.input.Pressed(ActionScatter) {
 			g.setGhostMode(Scatter)
 		}
 
-		if rl.IsKeyPressed(rl.KeyF) {
+		if g.input.Pressed(ActionFrighten) {
 			g.setGhostMode(Frightened)
-			g.frightTime = rl.GetTime() + FrightDuration
 		}
 	}
 
 	if g.paused {
+		g.lag = 0
+		if g.input.Pressed(ActionStep) {
+			g.step()
+		}
+		return
+	}
+
+	g.lag += min(float64(rl.GetFrameTime()), MaxFrameTime) * g.timeScale
+	for n := 0; g.lag >= TickTime && n < MaxTicksPerFrame; n++ {
+		g.step()
+		g.lag -= TickTime
+	}
+	// too far behind, e.g. at 4x on a slow machine: drop the backlog
+	g.lag = min(g.lag, TickTime)
+}
+
+// step runs one simulation tick. All game timers advance by TickTime per
+// tick, so slowing down, speeding up or stepping keep the rules intact.
+func (g *Game) step() {
+	p := g.player
+	if dir := p.controller.NextDirection(g); dir != None {
+		g.moved = true
+		if dir != p.nextDir {
+			g.emit(PlayerTurned{Dir: dir, Tick: g.tick})
+		}
+		p.nextDir = dir
+		p.nextVel = dir.Vector()
+	}
+
+	g.tick++
+	g.simTime += TickTime
+	if g.phase != PhasePlaying {
+		g.stepPhase()
+		g.flushTick()
 		return
 	}
+	g.levelTime = g.simTime - g.startTime
+
+	states := make([]GhostState, len(g.ghosts))
+	for i, ghost := range g.ghosts {
+		states[i] = ghost.state
+	}
 
 	p.Update(g)
 	for _, ghost := range g.ghosts {
@@ -80,22 +173,139 @@ func (g *Game) Update() {
 		if p.tile.Distance(ghost.tile) < 1 {
 			if ghost.state == Frightened {
 				ghost.state = Eaten
+				g.ghostsEaten++
+				points := GhostPoints << (min(g.ghostsEaten, 4) - 1)
+				p.addScore(g, points)
+				g.emit(GhostEaten{Ghost: ghost, Tile: ghost.tile, Points: points})
 				//g.paused = true
-				fmt.Printf("Player eats %s\n", ghost.name)
-			} else if !p.eaten {
+			} else if !p.eaten && !g.god {
 				p.eaten = true
-				fmt.Printf("Ghost %s eats player\n", ghost.name)
+				g.setPhase(PhaseDying, DeathTime)
+				g.emit(PlayerDied{Ghost: ghost, Tile: p.tile})
+			}
+		}
+
+	}
+
+	for i, ghost := range g.ghosts {
+		if ghost.state != states[i] {
+			g.emit(ModeChanged{Ghost: ghost, From: states[i], To: ghost.state})
+		}
+	}
+
+	g.updateFruit()
+
+	if g.dotsLeft == 0 && !p.eaten {
+		g.emit(LevelCleared{Level: g.level})
+		g.nextLevel()
+	}
+
+	g.flushTick()
+}
+
+// flushTick delivers the tick's events and traces it.
+func (g *Game) flushTick() {
+	g.bus.Flush(g)
+	if g.tracer != nil {
+		if err := g.tracer.Trace(g); err != nil {
+			timingLog.Warn("trace", "err", err)
+		}
+	}
+}
+
+// now is the simulated time in seconds, the clock for every game timer.
+func (g *Game) now() float64 {
+	return g.simTime
+}
+
+// setTimeScale clamps the scale to the supported range.
+func (g *Game) setTimeScale(scale float64) {
+	g.timeScale = min(max(scale, MinTimeScale), MaxTimeScale)
+}
+
+func (g *Game) updateFruit() {
+	p := g.player
+	if g.fruit == nil {
+		if g.fruitsSpawned < len(FruitDots) && g.dotsEaten >= FruitDots[g.fruitsSpawned] {
+			g.fruitsSpawned++
+			g.fruit = NewFruit(g)
+			if g.fruit != nil {
+				g.emit(FruitSpawned{Fruit: g.fruit.kind, Tile: g.fruit.tile})
 			}
 		}
+		return
+	}
+
+	if !g.fruit.Update(g) {
+		g.fruit = nil
+		return
+	}
+
+	if p.tile == g.fruit.tile {
+		points := g.fruit.kind.Points()
+		p.addScore(g, points)
+		g.emit(FruitEaten{Fruit: g.fruit.kind, Tile: g.fruit.tile, Points: points})
+		g.fruit = nil
+	}
+}
+
+// nextLevel moves on to the next level's board with a fresh set of dots.
+func (g *Game) nextLevel() {
+	g.level++
+	g.boardNum = g.levelBoard()
+	g.mapBoard()
+	g.resetActors()
+}
 
+// boardForLevel returns the arcade's maze order: two levels on the first
+// board, three on the second, four on the third and fourth, then the
+// third and fourth alternate every four levels.
+func boardForLevel(level int) int {
+	switch {
+	case level <= 2:
+		return 0
+	case level <= 5:
+		return 1
+	case level <= 9:
+		return 2
+	case level <= 13:
+		return 3
+	default:
+		return 2 + ((level-14)/4)%2
 	}
 }
 
+// resetActors puts the player and ghosts back at their starting tiles,
+// keeping score and lives, and gets ready to restart the level clock.
+func (g *Game) resetActors() {
+	old := g.player
+	g.player = NewPlayer(old.controller, g.board().Player)
+	g.player.score = old.score
+	g.player.lives = old.lives
+	g.player.cornering = g.rules.Cornering
+
+	for i, ghost := range g.ghosts {
+		g.ghosts[i] = NewGhost(g, ghost.behavior)
+	}
+
+	g.startTime = g.now()
+	g.levelTime = 0
+	g.frightTime = 0
+	g.dotsEaten = 0
+	g.fruit = nil
+	g.fruitsSpawned = 0
+	g.setPhase(PhaseReady, ReadyTime)
+}
+
 func (g *Game) setGhostMode(mode GhostState) {
 	for _, ghost := range g.ghosts {
 		ghost.state = mode
 		if mode == Frightened {
-			g.frightTime = rl.GetTime() + FrightDuration
+			g.frightTime = g.now() + g.rules.Preset.FrightTime()
 		}
 	}
+
+	if mode == Frightened {
+		g.ghostsEaten = 0
+	}
 }
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Problem is something wrong with a board. Warnings are allowed, e.g. an
+// asymmetric board may be intentional; anything else makes the board
+// unplayable or broken.
+type Problem struct {
+	Tile    Vec2i // NoTile for problems with the whole board
+	Msg     string
+	Warning bool
+}
+
+// NoTile is the tile of problems that aren't at a particular tile.
+var NoTile = Vec2i{X: -1, Y: -1}
+
+func (p Problem) String() string {
+	kind := "error"
+	if p.Warning {
+		kind = "warning"
+	}
+	if p.Tile == NoTile {
+		return fmt.Sprintf("%s: %s", kind, p.Msg)
+	}
+	return fmt.Sprintf("%s at %d,%d: %s", kind, p.Tile.X, p.Tile.Y, p.Msg)
+}
+
+// ValidateBoard checks that a board can be played:
+//   - the player, ghost start and house are on legal tiles
+//   - every dot can be reached from the player's start
+//   - every tunnel on one edge has a partner on the other edge
+//   - corridors have no dead ends
+//   - the house door connects the ghost start with the house
+//   - the left and right halves have the same number of dots and pellets
+//
+// Problems are reported in the order of the checks, row by row within a
+// check.
+func ValidateBoard(b *Board) []Problem {
+	v := validator{b: b, m: b.Maze}
+	v.spawns()
+	if len(v.problems) > 0 {
+		// the other checks start from the spawns
+		return v.problems
+	}
+
+	v.seen = reachable(b.Maze, b.Player)
+	v.dots()
+	v.tunnels()
+	v.deadEnds()
+	v.door()
+	v.symmetry()
+	return v.problems
+}
+
+// BoardError turns the errors, not the warnings, among problems into an
+// error.
+func BoardError(name string, problems []Problem) error {
+	var msgs []string
+	for _, p := range problems {
+		if !p.Warning {
+			msgs = append(msgs, p.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(name + ": " + strings.Join(msgs, "; "))
+}
+
+type validator struct {
+	b        *Board
+	m        Maze
+	seen     [][]bool // reachable from the player's start
+	problems []Problem
+}
+
+func (v *validator) error(t Vec2i, format string, args ...any) {
+	v.problems = append(v.problems, Problem{Tile: t, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warning(t Vec2i, format string, args ...any) {
+	v.problems = append(v.problems, Problem{Tile: t, Msg: fmt.Sprintf(format, args...), Warning: true})
+}
+
+func (v *validator) spawns() {
+	b := v.b
+	for _, spawn := range []struct {
+		name string
+		tile Vec2i
+	}{{"player start", b.Player}, {"ghost start", b.Ghost}, {"ghost house", b.House}} {
+		if !v.m.IsValidMove(spawn.tile) {
+			v.error(spawn.tile, "%s is not on an open tile", spawn.name)
+		} else if v.m[spawn.tile.Y][spawn.tile.X] == Tunnel {
+			v.error(spawn.tile, "%s is in a tunnel", spawn.name)
+		}
+	}
+	if b.Player == b.Ghost {
+		v.error(b.Player, "player and ghosts start on the same tile")
+	}
+}
+
+func (v *validator) dots() {
+	if !v.seen[v.b.Ghost.Y][v.b.Ghost.X] {
+		v.error(v.b.Ghost, "ghost start can't be reached from the player start")
+	}
+	if v.seen[v.b.House.Y][v.b.House.X] {
+		v.error(v.b.House, "the house is open, the player can walk in")
+	}
+
+	dots := 0
+	for y, row := range v.m {
+		for x, tile := range row {
+			if tile != Dot && tile != Power {
+				continue
+			}
+			dots++
+			if !v.seen[y][x] {
+				v.error(Vec2i{X: x, Y: y}, "%s can't be reached", tile.Name())
+			}
+		}
+	}
+	if dots == 0 {
+		v.error(NoTile, "no dots to eat")
+	}
+}
+
+func (v *validator) tunnels() {
+	right := len(v.m[0]) - 1
+	for y, row := range v.m {
+		for x, tile := range row {
+			if tile == Tunnel && x != 0 && x != right {
+				// tunnels may run several tiles in from the edge
+				if row[0] != Tunnel && row[right] != Tunnel {
+					v.error(Vec2i{X: x, Y: y}, "tunnel doesn't reach the edge")
+				}
+			}
+		}
+
+		// entities wrap at any open edge tile the player can get to
+		left, rightOpen := v.seen[y][0], v.seen[y][right]
+		if left && !rightOpen {
+			v.error(Vec2i{X: 0, Y: y}, "tunnel has no partner on the right edge")
+		} else if rightOpen && !left {
+			v.error(Vec2i{X: right, Y: y}, "tunnel has no partner on the left edge")
+		}
+	}
+}
+
+func (v *validator) deadEnds() {
+	for y, row := range v.m {
+		for x := range row {
+			t := Vec2i{X: x, Y: y}
+			if !v.seen[y][x] {
+				continue
+			}
+			exits := 0
+			for _, d := range []Direction{Up, Left, Down, Right} {
+				if v.m.IsValidMove(v.m.Wrap(d.GetNextTile(t))) {
+					exits++
+				}
+			}
+			if exits < 2 {
+				v.error(t, "dead end")
+			}
+		}
+	}
+}
+
+// door checks that the ghosts can go from their start through the door
+// into the house. The door is a wall to the player, so walk the maze again
+// with the door open.
+func (v *validator) door() {
+	b := v.b
+	if len(b.Doors) == 0 {
+		v.error(b.House, "the house has no door")
+		return
+	}
+
+	open := b.Maze.Clone()
+	for _, d := range b.Doors {
+		open[d.Y][d.X] = Empty
+	}
+	if !reachable(open, b.Ghost)[b.House.Y][b.House.X] {
+		v.error(b.Doors[0], "the house door doesn't lead from the ghost start to the house")
+	}
+}
+
+func (v *validator) symmetry() {
+	counts := map[Tile][2]int{}
+	half := len(v.m[0]) / 2
+	for _, row := range v.m {
+		for x, tile := range row {
+			c := counts[tile]
+			if x < half {
+				c[0]++
+			} else if x >= len(row)-half {
+				c[1]++
+			}
+			counts[tile] = c
+		}
+	}
+
+	for _, kind := range []struct {
+		tile Tile
+		name string
+	}{{Dot, "dots"}, {Power, "power pellets"}} {
+		if c := counts[kind.tile]; c[0] != c[1] {
+			v.warning(NoTile, "%d %s on the left half, %d on the right", c[0], kind.name, c[1])
+		}
+	}
+}
+
+// reachable marks every tile that can be walked to from a tile, wrapping
+// through the tunnels.
+func reachable(m Maze, from Vec2i) [][]bool {
+	seen := make([][]bool, len(m))
+	for y := range seen {
+		seen[y] = make([]bool, len(m[y]))
+	}
+
+	queue := []Vec2i{from}
+	seen[from.Y][from.X] = true
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for _, d := range []Direction{Up, Left, Down, Right} {
+			n := m.Wrap(d.GetNextTile(t))
+			if m.IsValidMove(n) && !seen[n.Y][n.X] {
+				seen[n.Y][n.X] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return seen
+}
+
+// validateMain is the validate subcommand: it checks maze files, or the
+// built-in and custom boards when none are given, prints every problem and
+// exits with 1 when a board has errors.
+func validateMain(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	strict := fs.Bool("strict", false, "treat warnings as errors")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s validate [-strict] [file.maze ...]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var boards []*Board
+	files := fs.Args()
+	if len(files) == 0 {
+		rl.SetTraceLogLevel(rl.LogWarning)
+		image := rl.LoadImage("frozen_tundra.png")
+		defer rl.UnloadImage(image)
+		g := &Game{image: image}
+		for i := range NumBuiltinBoards {
+			boards = append(boards, g.readBoard(i))
+		}
+		files, _ = filepath.Glob(filepath.Join(MazeDir, "*"+MazeExt))
+	}
+
+	failed := false
+	for _, file := range files {
+		b, err := readBoardFile(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+		boards = append(boards, b)
+	}
+
+	for _, b := range boards {
+		name := b.Name
+		if b.file != "" {
+			name = b.file
+		}
+		problems := ValidateBoard(b)
+		if len(problems) == 0 {
+			fmt.Printf("%s: ok\n", name)
+		}
+		for _, p := range problems {
+			fmt.Printf("%s: %s\n", name, p)
+			if !p.Warning || *strict {
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// testMaze is a small board that passes every check.
+const testMaze = `XXXXXXXXXXX
+X*...G...*X
+X.XXX-XXX.X
+X.XXXHXXX.X
+X.XXXXXXX.X
+@....P....@
+X.XXXXXXX.X
+X*.......*X
+XXXXXXXXXXX
+`
+
+// brokenMaze is testMaze with row y replaced.
+func brokenMaze(y int, row string) string {
+	lines := strings.Split(testMaze, "\n")
+	lines[y] = row
+	return strings.Join(lines, "\n")
+}
+
+func TestValidateBuiltinBoards(t *testing.T) {
+	if _, err := os.Stat("frozen_tundra.png"); err != nil {
+		t.Skip("no spritesheet:", err)
+	}
+	image := rl.LoadImage("frozen_tundra.png")
+	defer rl.UnloadImage(image)
+	g := &Game{image: image}
+	for i := range NumBuiltinBoards {
+		b := g.readBoard(i)
+		if problems := ValidateBoard(b); len(problems) > 0 {
+			t.Errorf("%s: %v", b.Name, problems)
+		}
+	}
+}
+
+func TestValidateBoard(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		maze    string
+		edit    func(*Board)
+		want    string // "" for no problems
+		warning bool
+	}{
+		{name: "ok", maze: testMaze},
+		{
+			name: "unreachable dot",
+			maze: brokenMaze(3, "X.X.XHXXX.X"),
+			want: "at 3,3: dot can't be reached",
+		},
+		{
+			name: "unpaired tunnel",
+			maze: brokenMaze(5, "@....P....X"),
+			want: "tunnel has no partner on the right edge",
+		},
+		{
+			name: "dead end",
+			maze: brokenMaze(4, "X.X XXXXX.X"),
+			want: "at 3,4: dead end",
+		},
+		{
+			name: "door cut off",
+			maze: brokenMaze(2, "X.XXXXXXX.X"),
+			edit: func(b *Board) { b.Doors = []Vec2i{{X: 3, Y: 2}} },
+			want: "house door doesn't lead from the ghost start",
+		},
+		{
+			name: "spawn on a wall",
+			maze: testMaze,
+			edit: func(b *Board) { b.Player = Vec2i{X: 0, Y: 0} },
+			want: "player start is not on an open tile",
+		},
+		{
+			name:    "asymmetric counts",
+			maze:    brokenMaze(5, "@. ..P....@"),
+			want:    "13 dots on the left half, 14 on the right",
+			warning: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseBoard(tt.name, tt.maze)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.edit != nil {
+				tt.edit(b)
+			}
+
+			problems := ValidateBoard(b)
+			if tt.want == "" {
+				if len(problems) > 0 {
+					t.Errorf("problems %v, want none", problems)
+				}
+				return
+			}
+			for _, p := range problems {
+				if strings.Contains(p.String(), tt.want) {
+					if p.Warning != tt.warning {
+						t.Errorf("%s: warning %t, want %t", p, p.Warning, tt.warning)
+					}
+					return
+				}
+			}
+			t.Errorf("problems %v, want %q", problems, tt.want)
+		})
+	}
+}
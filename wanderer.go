@@ -0,0 +1,53 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Wanderer (Blue)
+//    Chase:
+//       Ignores Player. Picks a random corner or junction of the maze and
+//       strolls there, choosing a new one on arrival or every few seconds.
+//       Harmless on its own but unpredictable in a pack.
+//    Scatter:
+//       Moves to the bottom-right corner of the maze.
+
+const WanderTime = 5.0 // seconds before picking a new destination
+
+func (b *Wanderer) Id() GhostId {
+	return WandererId
+}
+
+func (b *Wanderer) Color() rl.Color {
+	return rl.Lime
+}
+
+func (b *Wanderer) StartingTile(game *Game) Vec2i {
+	return Vec2i{X: 12, Y: 11}
+}
+
+func (b *Wanderer) StartingDir(game *Game) Direction {
+	return Left
+}
+
+func (b *Wanderer) Sprite() Vec2i {
+	return Vec2i{X: 520, Y: 96}
+}
+
+func (b *Wanderer) Chase(game *Game, self *Ghost) Vec2i {
+	if self.tile == b.target || game.levelTime > b.until || b.until == 0 {
+		nodes := game.graph.Nodes()
+		if len(nodes) > 0 {
+			b.target = nodes[game.rng.IntN(len(nodes))]
+		}
+		b.until = game.levelTime + WanderTime
+	}
+
+	return b.target
+}
+
+func (b *Wanderer) Scatter(game *Game, _ *Ghost) Vec2i {
+	return Vec2i{X: 26, Y: 29} // depends on board
+}
+
+func (b *Wanderer) ExitHouse(game *Game, _ *Ghost) bool {
+	return game.levelTime > 3.0
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+)
+
+// WSG emulates the Namco Waveform Sound Generator in the arcade board: three
+// voices, each playing one of eight 32 sample, 4 bit wavetables at a given
+// frequency and volume. The game's sound effects are programs of wsgSteps
+// that change a voice's waveform, frequency and volume once per frame, which
+// is how the original sound routines worked, so no recorded samples are
+// needed.
+type WSG struct {
+	SampleRate int
+	voices     [WSGVoices]wsgVoice
+	tickIn     float64 // samples left until the next 60Hz tick
+}
+
+const (
+	WSGClock     = 96000   // Hz, the chip's sample clock
+	WSGFreqRange = 1 << 20 // frequency registers are 20 bits
+	WSGVoices    = 3
+	WSGFrameRate = 60
+	WSGMaxLength = 10 * WSGFrameRate // frames, when rendering offline
+
+	wsgHzScale = float64(WSGFreqRange) / WSGClock
+)
+
+// wsgWaveforms approximate the eight wavetables of the sound PROM.
+var wsgWaveforms = [8][32]byte{
+	// 0: sine
+	{7, 9, 10, 11, 12, 13, 14, 15, 15, 15, 14, 13, 12, 11, 10, 9, 7, 5, 4, 3, 2, 1, 0, 0, 0, 0, 0, 1, 2, 3, 4, 5},
+	// 1: rounded pulse, used for munching
+	{7, 12, 14, 15, 15, 15, 14, 12, 7, 2, 1, 0, 0, 0, 1, 2, 7, 9, 10, 11, 11, 11, 10, 9, 7, 4, 3, 2, 2, 2, 3, 4},
+	// 2: square
+	{15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	// 3: triangle
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	// 4: sawtooth
+	{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 8, 8, 9, 9, 10, 10, 11, 11, 12, 12, 13, 13, 14, 14, 15, 15},
+	// 5: narrow pulse
+	{15, 15, 15, 15, 15, 15, 15, 15, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	// 6: sine with second harmonic, used for sirens
+	{7, 11, 13, 14, 15, 14, 13, 11, 9, 8, 8, 8, 9, 10, 10, 9, 7, 5, 4, 5, 6, 6, 6, 5, 3, 1, 0, 1, 2, 3, 4, 5},
+	// 7: organ, used for music
+	{7, 13, 15, 13, 10, 9, 10, 12, 12, 10, 7, 5, 4, 5, 7, 8, 7, 6, 7, 9, 10, 9, 7, 4, 2, 2, 4, 5, 4, 1, 0, 1},
+}
+
+// wsgStep is one segment of an effect. For Frames frames the voice plays
+// Wave, starting at Freq and Volume (0-15) and adding FreqStep and VolStep
+// every frame.
+type wsgStep struct {
+	Frames   int
+	Wave     int
+	Freq     int // 20 bit frequency register, see hz
+	FreqStep int
+	Volume   int
+	VolStep  int
+}
+
+// wsgEffect is a sound effect: one program of steps per voice it uses, nil
+// for voices it leaves alone.
+type wsgEffect [WSGVoices][]wsgStep
+
+type wsgVoice struct {
+	playing bool
+	sound   SoundId
+	steps   []wsgStep
+	step    int
+	frame   int
+	wave    int
+	freq    int
+	volume  int
+	phase   float64
+}
+
+// hz converts a frequency in Hz to the chip's frequency register.
+func hz(f float64) int {
+	return int(f * wsgHzScale)
+}
+
+func NewWSG(sampleRate int) *WSG {
+	return &WSG{SampleRate: sampleRate}
+}
+
+// Play starts the effect for a sound, taking over the voices it uses.
+func (w *WSG) Play(id SoundId) {
+	for i, steps := range wsgEffects[id] {
+		if steps == nil {
+			continue
+		}
+		w.voices[i] = wsgVoice{playing: true, sound: id, steps: steps}
+		w.voices[i].load()
+	}
+}
+
+func (w *WSG) Stop(id SoundId) {
+	for i := range w.voices {
+		if w.voices[i].playing && w.voices[i].sound == id {
+			w.voices[i].playing = false
+		}
+	}
+}
+
+func (w *WSG) IsPlaying(id SoundId) bool {
+	for _, v := range w.voices {
+		if v.playing && v.sound == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Idle reports whether all voices are silent.
+func (w *WSG) Idle() bool {
+	for _, v := range w.voices {
+		if v.playing {
+			return false
+		}
+	}
+	return true
+}
+
+// Render fills out with mono samples in [-1, 1], advancing the effect
+// programs at 60 frames per second of audio.
+func (w *WSG) Render(out []float32) {
+	step := float64(WSGClock) / float64(WSGFreqRange) / float64(w.SampleRate)
+	for i := range out {
+		if w.tickIn <= 0 {
+			w.tick()
+			w.tickIn += float64(w.SampleRate) / WSGFrameRate
+		}
+		w.tickIn--
+
+		var mix float32
+		for j := range w.voices {
+			v := &w.voices[j]
+			if !v.playing || v.volume <= 0 {
+				continue
+			}
+			v.phase += float64(v.freq) * step
+			v.phase -= float64(int(v.phase))
+			sample := float32(wsgWaveforms[v.wave][int(v.phase*32)]) - 7.5
+			mix += sample / 7.5 * float32(v.volume) / 15
+		}
+		out[i] = mix / WSGVoices
+	}
+}
+
+// tick advances every voice by one frame.
+func (w *WSG) tick() {
+	for i := range w.voices {
+		v := &w.voices[i]
+		if !v.playing {
+			continue
+		}
+
+		v.frame++
+		s := v.steps[v.step]
+		if v.frame < s.Frames {
+			// the register can't sweep below zero or past 20 bits
+			v.freq = min(max(v.freq+s.FreqStep, 0), WSGFreqRange-1)
+			v.volume = min(max(v.volume+s.VolStep, 0), 15)
+			continue
+		}
+
+		v.step++
+		v.frame = 0
+		if v.step >= len(v.steps) {
+			v.playing = false
+			continue
+		}
+		v.load()
+	}
+}
+
+func (v *wsgVoice) load() {
+	s := v.steps[v.step]
+	v.wave = s.Wave
+	v.freq = s.Freq
+	v.volume = s.Volume
+}
+
+// RenderEffect plays one sound effect on a fresh chip and returns it as 16
+// bit PCM, for saving to WAV or loading as a raylib sound.
+func RenderEffect(id SoundId, sampleRate int) []int16 {
+	w := NewWSG(sampleRate)
+	w.Play(id)
+
+	frame := make([]float32, sampleRate/WSGFrameRate)
+	var result []int16
+	for range WSGMaxLength {
+		if w.Idle() {
+			break
+		}
+		w.Render(frame)
+		for _, s := range frame {
+			result = append(result, int16(s*32767))
+		}
+	}
+	return result
+}
+
+// WriteWAV saves mono 16 bit PCM samples as a WAV file.
+func WriteWAV(file string, samples []int16, sampleRate int) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	size := uint32(2 * len(samples))
+	header := []any{
+		[4]byte{'R', 'I', 'F', 'F'}, 36 + size, [4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '}, uint32(16), uint16(1), uint16(1),
+		uint32(sampleRate), uint32(2 * sampleRate), uint16(2), uint16(16),
+		[4]byte{'d', 'a', 't', 'a'}, size,
+	}
+	for _, v := range header {
+		if err := binary.Write(f, binary.LittleEndian, v); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	if err := binary.Write(f, binary.LittleEndian, samples); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRenderEffect(t *testing.T) {
+	const sampleRate = 22050
+	for i := range NumSounds {
+		id := SoundId(i)
+		t.Run(id.String(), func(t *testing.T) {
+			pcm := RenderEffect(id, sampleRate)
+			if len(pcm) == 0 {
+				t.Fatal("no samples")
+			}
+			if !slices.ContainsFunc(pcm, func(s int16) bool { return s != 0 }) {
+				t.Error("silent")
+			}
+			if again := RenderEffect(id, sampleRate); !slices.Equal(pcm, again) {
+				t.Error("rendered differently the second time")
+			}
+		})
+	}
+}
+
+// A frequency sweeping down past zero stops at zero instead of running the
+// phase backwards off the wavetable.
+func TestWSGNegativeFreq(t *testing.T) {
+	w := NewWSG(22050)
+	w.voices[0] = wsgVoice{playing: true, steps: []wsgStep{
+		{Frames: 10, Wave: 3, Freq: hz(200), FreqStep: -hz(100), Volume: 15},
+	}}
+	w.voices[0].load()
+
+	w.Render(make([]float32, 22050/WSGFrameRate*10))
+	if freq := w.voices[0].freq; freq != 0 {
+		t.Errorf("frequency register %d, want 0", freq)
+	}
+}
+
+// wsgAudio plays an Audio's sounds on a bare WSG, a frame per Update.
+type wsgAudio struct {
+	*WSG
+	frame []float32
+}
+
+func (w *wsgAudio) LoadFile(SoundId, string) error { return nil }
+func (w *wsgAudio) Update()                        { w.Render(w.frame) }
+func (w *wsgAudio) SetVolume(float32)              {}
+func (w *wsgAudio) Close()                         {}
+
+// The fruit's bounce shares the effects voice, so it must not restart over
+// a ghost being eaten, only once the effect is over.
+func TestFruitBounceWaitsForEffects(t *testing.T) {
+	chip := &wsgAudio{WSG: NewWSG(22050), frame: make([]float32, 22050/WSGFrameRate)}
+	a := NewAudio(chip)
+	g := &Game{fruit: &Fruit{}, phase: PhasePlaying}
+
+	a.HandleEvent(g, 0, GhostEaten{})
+	for frame := range 20 {
+		a.Update(g)
+		if !chip.IsPlaying(SoundGhostEaten) {
+			t.Fatalf("ghost eaten sound cut off on frame %d", frame)
+		}
+	}
+
+	for range 20 {
+		a.Update(g)
+	}
+	if !chip.IsPlaying(SoundFruitBounce) {
+		t.Error("fruit bounce didn't come back after the ghost eaten sound")
+	}
+}